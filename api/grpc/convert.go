@@ -0,0 +1,174 @@
+package grpcapi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "geminizer-enterprise/api/proto/geminizer/v1"
+	v1 "geminizer-enterprise/api/v1"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+)
+
+var tiersToProto = map[services.Tier]pb.Tier{
+	services.TierEnhanced:   pb.Tier_TIER_ENHANCED,
+	services.TierFinal:      pb.Tier_TIER_FINAL,
+	services.TierEnterprise: pb.Tier_TIER_ENTERPRISE,
+	services.Tier3D:         pb.Tier_TIER_3D,
+	services.TierMaster:     pb.Tier_TIER_MASTER,
+}
+
+func tierFromProto(tier pb.Tier) (services.Tier, error) {
+	if tier == pb.Tier_TIER_UNSPECIFIED {
+		return services.TierEnhanced, nil
+	}
+	for name, value := range tiersToProto {
+		if value == tier {
+			return name, nil
+		}
+	}
+	return "", status.Errorf(codes.InvalidArgument, "unknown tier %v", tier)
+}
+
+func toTierRequest(tier services.Tier, req *pb.GenerateRequest) (services.TierRequest, error) {
+	request := services.TierRequest{
+		Tier:       tier,
+		Prompt:     req.GetPrompt(),
+		Style:      req.GetStyle(),
+		Filter:     req.GetFilter(),
+		ShotType:   req.GetShotType(),
+		Mood:       req.GetMood(),
+		Explain:    req.GetExplain(),
+		WebhookURL: req.GetWebhookUrl(),
+		DryRun:     req.GetDryRun(),
+
+		Provider:       req.GetProvider(),
+		NegativePrompt: req.GetNegativePrompt(),
+		AspectRatio:    req.GetAspectRatio(),
+		CustomStyle:    req.GetCustomStyle(),
+		Stages:         req.GetStages(),
+		Profile:        req.GetProfile(),
+	}
+
+	if err := fromStruct(req.GetOptions(), &request.Options); err != nil {
+		return request, status.Errorf(codes.InvalidArgument, "invalid options: %v", err)
+	}
+
+	// The same schema checks as the HTTP API: enums, lengths and limits
+	if fields := v1.ValidateRequest(&request); len(fields) > 0 {
+		problems := make([]string, len(fields))
+		for i, field := range fields {
+			problems[i] = field.Field + ": " + field.Message
+		}
+		return request, status.Errorf(codes.InvalidArgument, "request validation failed: %s", strings.Join(problems, "; "))
+	}
+	return request, nil
+}
+
+func toGenerateResponse(result *services.TierResult) (*pb.GenerateResponse, error) {
+	response := &pb.GenerateResponse{
+		Tier:        tiersToProto[result.Tier],
+		FinalPrompt: result.FinalPrompt,
+		ImageUrl:    result.ImageURL,
+		DryRun:      result.DryRun,
+	}
+
+	var err error
+	if response.Analysis, err = toStruct(result.Analysis); err != nil {
+		return nil, err
+	}
+	if response.Response, err = toStruct(result.Response); err != nil {
+		return nil, err
+	}
+	if response.Trace, err = toStruct(result.Trace); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func toProgressEvent(event domain.ProgressEvent) *pb.ProgressEvent {
+	data, _ := toStruct(event.Data)
+	return &pb.ProgressEvent{
+		Stage:     event.Stage,
+		Message:   event.Message,
+		Data:      data,
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+}
+
+// toStruct converts through JSON, so gRPC clients see exactly the fields
+// the HTTP API returns
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		// null or a non-object value has no Struct form
+		return nil, nil
+	}
+	return structpb.NewStruct(fields)
+}
+
+func toStructList(v interface{}) ([]*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	result := make([]*structpb.Struct, 0, len(items))
+	for _, item := range items {
+		converted, err := structpb.NewStruct(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+func fromStruct(s *structpb.Struct, out interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// statusError maps service errors onto gRPC codes the way the HTTP API
+// maps them onto status codes
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		switch appErr.Code {
+		case domain.ErrCodeQuotaExceeded, domain.ErrCodeRateLimited:
+			return status.Error(codes.ResourceExhausted, appErr.Error())
+		case domain.ErrCodeSafetyRejected, domain.ErrCodeQAFailed:
+			return status.Error(codes.FailedPrecondition, appErr.Error())
+		case domain.ErrCodeForbidden:
+			return status.Error(codes.PermissionDenied, appErr.Error())
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
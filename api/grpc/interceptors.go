@@ -0,0 +1,172 @@
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "geminizer-enterprise/api/proto/geminizer/v1"
+	"geminizer-enterprise/internal/auth"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/quota"
+	"geminizer-enterprise/internal/tenant"
+)
+
+// publicMethods mirror the HTTP routes that need no token
+var publicMethods = map[string]bool{
+	pb.GeminizerService_GetSystemStatus_FullMethodName: true,
+}
+
+// generationMethods draw from the caller's quota, like the HTTP
+// generation group
+var generationMethods = map[string]bool{
+	pb.GeminizerService_GenerateEnhanced_FullMethodName:   true,
+	pb.GeminizerService_GenerateFinal_FullMethodName:      true,
+	pb.GeminizerService_GenerateEnterprise_FullMethodName: true,
+	pb.GeminizerService_Generate3D_FullMethodName:         true,
+	pb.GeminizerService_GenerateMaster_FullMethodName:     true,
+	pb.GeminizerService_GenerateStream_FullMethodName:     true,
+}
+
+// Policy applies the HTTP middleware's authentication, tenancy, rate
+// limits and quotas to every RPC
+type Policy struct {
+	authenticator auth.Authenticator
+	tenants       *tenant.Registry
+	limiter       *quota.Limiter
+}
+
+func NewPolicy(authenticator auth.Authenticator, tenants *tenant.Registry, limiter *quota.Limiter) *Policy {
+	return &Policy{authenticator: authenticator, tenants: tenants, limiter: limiter}
+}
+
+// ServerOptions installs the policy as interceptors
+func (p *Policy) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(p.unary),
+		grpc.ChainStreamInterceptor(p.stream),
+	}
+}
+
+func (p *Policy) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, refund, err := p.authorize(ctx, info.FullMethod, func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	if refund != nil && (err != nil || isDryRun(req)) {
+		refund()
+	}
+	return resp, err
+}
+
+func (p *Policy) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, refund, err := p.authorize(ss.Context(), info.FullMethod, ss.SetHeader)
+	if err != nil {
+		return err
+	}
+
+	wrapped := &policyStream{ServerStream: ss, ctx: ctx}
+	err = handler(srv, wrapped)
+	if refund != nil && (err != nil || wrapped.dryRun) {
+		refund()
+	}
+	return err
+}
+
+// isDryRun reports whether a generation request asked for a dry run,
+// which draws no image and so is not charged
+func isDryRun(req interface{}) bool {
+	switch req := req.(type) {
+	case *pb.GenerateRequest:
+		return req.GetDryRun()
+	case *pb.GenerateStreamRequest:
+		return req.GetRequest().GetDryRun()
+	}
+	return false
+}
+
+// authorize returns the context the handler runs with: the caller's
+// identity attached and their rate limit and quota charged. refund gives
+// the quota charge back when the call fails or was a dry run; it is nil
+// when nothing was charged.
+func (p *Policy) authorize(ctx context.Context, method string, setHeader func(metadata.MD) error) (context.Context, func(), error) {
+	if publicMethods[method] {
+		return ctx, nil, nil
+	}
+
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	identity, err := p.authenticator.Authenticate(authorization)
+	if err != nil {
+		return nil, nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if p.tenants != nil {
+		identity = p.tenants.Resolve(identity)
+	}
+	ctx = services.WithIdentity(ctx, identity)
+
+	subject := quota.Subject{UserID: identity.UserID, TenantID: identity.TenantID, Plan: identity.Plan}
+
+	// Like the HTTP middleware, a failing limit store does not block calls
+	if decision, err := p.limiter.Allow(ctx, subject); err == nil {
+		if decision.Limit > 0 {
+			setHeader(metadata.Pairs(
+				"ratelimit-limit", strconv.Itoa(decision.Limit),
+				"ratelimit-remaining", strconv.Itoa(decision.Remaining),
+				"ratelimit-reset", strconv.Itoa(int(decision.Reset.Seconds())),
+			))
+		}
+		if !decision.Allowed {
+			return nil, nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+	}
+
+	var refund func()
+	if generationMethods[method] {
+		chargedAt := time.Now()
+		if _, err := p.limiter.Consume(ctx, subject, 1); err != nil {
+			if domain.ErrorCode(err) == domain.ErrCodeQuotaExceeded {
+				return nil, nil, statusError(err)
+			}
+			return nil, nil, status.Error(codes.Unavailable, "quota store unavailable")
+		}
+		refund = func() { p.limiter.Refund(ctx, subject, 1, chargedAt) }
+	}
+
+	return ctx, refund, nil
+}
+
+// policyStream swaps in the authorized context and notes whether the
+// request it received was a dry run
+type policyStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	dryRun bool
+}
+
+func (s *policyStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *policyStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && isDryRun(m) {
+		s.dryRun = true
+	}
+	return err
+}
@@ -0,0 +1,187 @@
+// Package grpcapi serves the GeminizerService gRPC API on the same service
+// graph as the HTTP API.
+package grpcapi
+
+//go:generate protoc --proto_path=../proto --go_out=../proto --go_opt=paths=source_relative --go-grpc_out=../proto --go-grpc_opt=paths=source_relative geminizer/v1/geminizer.proto
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "geminizer-enterprise/api/proto/geminizer/v1"
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/webhooks"
+)
+
+type Server struct {
+	pb.UnimplementedGeminizerServiceServer
+
+	graph     *services.ServiceGraph
+	aiManager *management.ManagerAgent
+}
+
+func NewServer(graph *services.ServiceGraph, aiManager *management.ManagerAgent) *Server {
+	return &Server{
+		graph:     graph,
+		aiManager: aiManager,
+	}
+}
+
+func (s *Server) GenerateEnhanced(ctx context.Context, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	return s.generate(ctx, services.TierEnhanced, req)
+}
+
+func (s *Server) GenerateFinal(ctx context.Context, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	return s.generate(ctx, services.TierFinal, req)
+}
+
+func (s *Server) GenerateEnterprise(ctx context.Context, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	return s.generate(ctx, services.TierEnterprise, req)
+}
+
+func (s *Server) Generate3D(ctx context.Context, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	return s.generate(ctx, services.Tier3D, req)
+}
+
+func (s *Server) GenerateMaster(ctx context.Context, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	return s.generate(ctx, services.TierMaster, req)
+}
+
+func (s *Server) generate(ctx context.Context, tier services.Tier, req *pb.GenerateRequest) (*pb.GenerateResponse, error) {
+	request, err := newTierRequest(tier, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.graph.Generate(ctx, request)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toGenerateResponse(result)
+}
+
+// GenerateStream mirrors POST /generate/stream as a server stream. Progress
+// is sent from inside the pipeline, which runs on this goroutine, so Send
+// is never called concurrently.
+func (s *Server) GenerateStream(req *pb.GenerateStreamRequest, stream pb.GeminizerService_GenerateStreamServer) error {
+	tier, err := tierFromProto(req.GetTier())
+	if err != nil {
+		return err
+	}
+
+	request, err := newTierRequest(tier, req.GetRequest())
+	if err != nil {
+		return err
+	}
+
+	ctx := services.WithProgress(stream.Context(), func(event domain.ProgressEvent) {
+		stream.Send(&pb.GenerateEvent{
+			Event: &pb.GenerateEvent_Progress{Progress: toProgressEvent(event)},
+		})
+	})
+
+	result, err := s.graph.Generate(ctx, request)
+	if err != nil {
+		return statusError(err)
+	}
+
+	response, err := toGenerateResponse(result)
+	if err != nil {
+		return statusError(err)
+	}
+	return stream.Send(&pb.GenerateEvent{
+		Event: &pb.GenerateEvent_Result{Result: response},
+	})
+}
+
+func (s *Server) ValidatePrompt(ctx context.Context, req *pb.ValidatePromptRequest) (*pb.ValidatePromptResponse, error) {
+	if strings.TrimSpace(req.GetPrompt()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "prompt is required")
+	}
+
+	validation, err := toStruct(s.graph.Master.ValidatePrompt(req.GetPrompt()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ValidatePromptResponse{Validation: validation}, nil
+}
+
+func (s *Server) GetStyleRecommendations(ctx context.Context, req *pb.StyleRecommendationsRequest) (*pb.RecommendationsResponse, error) {
+	var styleContext ai.StyleContext
+	if err := fromStruct(req.GetContext(), &styleContext); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid style context: %v", err)
+	}
+
+	recommendations, err := toStructList(s.graph.Master.GetStyleRecommendations(styleContext))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.RecommendationsResponse{Recommendations: recommendations}, nil
+}
+
+func (s *Server) GetPoseRecommendations(ctx context.Context, req *pb.PoseRecommendationsRequest) (*pb.RecommendationsResponse, error) {
+	var poseContext ai.PoseContext
+	if err := fromStruct(req.GetContext(), &poseContext); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pose context: %v", err)
+	}
+
+	recommendations, err := toStructList(s.graph.Enterprise3D.GetPoseRecommendations(poseContext))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.RecommendationsResponse{Recommendations: recommendations}, nil
+}
+
+func (s *Server) VerifyPosePhysics(ctx context.Context, req *pb.VerifyPosePhysicsRequest) (*pb.VerifyPosePhysicsResponse, error) {
+	if strings.TrimSpace(req.GetPoseDescription()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "pose_description is required")
+	}
+
+	validation, err := toStruct(s.graph.Enterprise3D.VerifyPosePhysics(req.GetPoseDescription()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.VerifyPosePhysicsResponse{Validation: validation}, nil
+}
+
+func (s *Server) GetSystemStatus(ctx context.Context, req *pb.GetSystemStatusRequest) (*pb.SystemStatus, error) {
+	systemStatus := s.aiManager.GetSystemStatus()
+
+	response := &pb.SystemStatus{
+		OverallHealth:      systemStatus.OverallHealth,
+		AveragePerformance: systemStatus.AveragePerformance,
+		SystemLoad:         systemStatus.SystemLoad,
+		UptimeSeconds:      int64(systemStatus.Uptime.Seconds()),
+		TotalAgents:        int32(systemStatus.TotalAgents),
+	}
+	for _, agent := range systemStatus.Agents {
+		response.Agents = append(response.Agents, &pb.AgentStatus{
+			Id:          agent.ID,
+			Name:        agent.Name,
+			Role:        agent.Role,
+			Status:      agent.Status,
+			HealthScore: agent.HealthScore,
+			Performance: agent.Performance,
+		})
+	}
+	return response, nil
+}
+
+// newTierRequest applies the same checks as the HTTP generate handlers
+func newTierRequest(tier services.Tier, req *pb.GenerateRequest) (services.TierRequest, error) {
+	if req == nil || strings.TrimSpace(req.GetPrompt()) == "" {
+		return services.TierRequest{}, status.Error(codes.InvalidArgument, "prompt is required")
+	}
+	if req.GetWebhookUrl() != "" {
+		if err := webhooks.ValidateURL(req.GetWebhookUrl()); err != nil {
+			return services.TierRequest{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return toTierRequest(tier, req)
+}
@@ -22,6 +22,10 @@ func (h *ImageHandler) GetAgentDetails(c *gin.Context) {
 	
 	// Get detailed agent information
 	agentInfo := h.aiManager.GetAgentDetails(agentID)
+	if agentInfo == nil {
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, "agent not found: "+agentID)
+		return
+	}
 	
 	c.JSON(http.StatusOK, gin.H{
 		"agent":     agentInfo,
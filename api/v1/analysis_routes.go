@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/ai"
+)
+
+type promptRequest struct {
+	Prompt string `json:"prompt" validate:"required,min=1,max=4000"`
+}
+
+// Health is the liveness endpoint used by the docker-compose healthcheck
+func (h *ImageHandler) Health(c *gin.Context) {
+	health := h.healthMonitor.CheckSystemHealth()
+
+	status := http.StatusOK
+	if health.Status == "unhealthy" {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, gin.H{
+		"status":    health.Status,
+		"uptime":    time.Since(h.startTime).String(),
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// SafetyCheck backs the SafetyMonitor component
+func (h *ImageHandler) SafetyCheck(c *gin.Context) {
+	var request promptRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	analysis := h.safetyAnalyzer.AnalyzeAdvancedSafety(request.Prompt)
+	issues := analysis.Issues
+	var recoverySuggestions []string
+
+	if h.explicitDetector.ContainsExplicitContent(request.Prompt) {
+		issues = append(issues, "Prompt contains explicit content")
+		recoverySuggestions = append(recoverySuggestions, h.explicitDetector.SafeAlternative(request.Prompt))
+		h.moderation.RecordTenantViolation(tenantID(c), userID(c), "explicit_content", request.Prompt)
+	}
+
+	if !analysis.IsSafe {
+		if safePrompt, err := h.safetyAnalyzer.MakeSafe(request.Prompt, analysis.Issues); err == nil {
+			recoverySuggestions = append(recoverySuggestions, safePrompt)
+		}
+		h.moderation.RecordTenantViolation(tenantID(c), userID(c), "safety_check", request.Prompt)
+	}
+
+	if violations := h.graph.CheckPolicy(c.Request.Context(), request.Prompt, ""); len(violations) > 0 {
+		issues = append(issues, violations...)
+		h.moderation.RecordTenantViolation(tenantID(c), userID(c), "workspace_policy", request.Prompt)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"is_safe":              len(issues) == 0,
+		"issues":               issues,
+		"recovery_suggestions": recoverySuggestions,
+		"timestamp":            time.Now().UTC(),
+	})
+}
+
+// ExpertCommand backs the ExpertInterface component
+func (h *ImageHandler) ExpertCommand(c *gin.Context) {
+	var request expertCommandRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	if request.Expertise == "" {
+		request.Expertise = "expert"
+	}
+
+	response := h.consciousUI.ProcessUserCommand(request.Command, ai.UIState{
+		UserExpertise:   request.Expertise,
+		UserPreferences: make(map[string]interface{}),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     response.Message,
+		"suggestions": response.Suggestions,
+		"intent":      response.DetectedIntent,
+		"confidence":  response.Confidence,
+	})
+}
+
+// AnalyzeProfessional backs the ProfessionalDemo component
+func (h *ImageHandler) AnalyzeProfessional(c *gin.Context) {
+	var request promptRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	enhanced := h.promptEnhancer.EnhanceProfessionalPrompt(request.Prompt)
+
+	c.JSON(http.StatusOK, gin.H{
+		"analysis":        enhanced.ProfessionalAnalysis,
+		"enhanced_prompt": enhanced.EnhancedPrompt,
+		"quality_score":   enhanced.QualityScore,
+		"improvements":    enhanced.Improvements,
+	})
+}
@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"geminizer-enterprise/internal/auth"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+)
+
+const identityKey = "identity"
+
+// Groups splits the v1 routes by who may call them. Generation routes
+// additionally honour Idempotency-Key and draw from the caller's quota;
+// batch routes honour Idempotency-Key and charge the quota per item.
+type Groups struct {
+	Public     *gin.RouterGroup
+	User       *gin.RouterGroup
+	Generation *gin.RouterGroup
+	Batch      *gin.RouterGroup
+	Admin      *gin.RouterGroup
+}
+
+// Middleware is the per-request policy applied to the authenticated groups.
+// Nil entries are skipped.
+type Middleware struct {
+	Authenticate gin.HandlerFunc
+	Tenancy      gin.HandlerFunc
+	RateLimit    gin.HandlerFunc
+	Idempotency  gin.HandlerFunc
+	Quota        gin.HandlerFunc
+}
+
+// NewGroups layers the middleware and the admin role check onto group.
+// Idempotency runs before the quota so a replayed response is not charged.
+func NewGroups(group *gin.RouterGroup, middleware Middleware) Groups {
+	user := group.Group("", handlers(middleware.Authenticate, middleware.Tenancy, middleware.RateLimit)...)
+	return Groups{
+		Public:     group,
+		User:       user,
+		Generation: user.Group("", handlers(middleware.Idempotency, middleware.Quota)...),
+		Batch:      user.Group("", handlers(middleware.Idempotency)...),
+		Admin:      user.Group("", RequireRole(domain.RoleAdmin)),
+	}
+}
+
+func handlers(candidates ...gin.HandlerFunc) []gin.HandlerFunc {
+	var result []gin.HandlerFunc
+	for _, handler := range candidates {
+		if handler != nil {
+			result = append(result, handler)
+		}
+	}
+	return result
+}
+
+// Authenticate validates the bearer token and puts the caller's identity
+// in both the gin context and the request context the services see
+func Authenticate(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := authenticator.Authenticate(authorization(c))
+		if err != nil {
+			challenge := `Bearer realm="geminizer"`
+			if !errors.Is(err, auth.ErrMissingToken) {
+				challenge += `, error="invalid_token"`
+			}
+			c.Header("WWW-Authenticate", challenge)
+			abortError(c, http.StatusUnauthorized, domain.ErrCodeUnauthenticated, err.Error())
+			return
+		}
+
+		setIdentity(c, identity)
+		c.Next()
+	}
+}
+
+// authorization is the Authorization header or, for WebSocket upgrades
+// (browsers cannot set headers on those), the access_token parameter
+func authorization(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		return header
+	}
+	if token := c.Query("access_token"); token != "" && websocket.IsWebSocketUpgrade(c.Request) {
+		return "Bearer " + token
+	}
+	return ""
+}
+
+// RequireRole rejects callers whose identity lacks role
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := identityFrom(c)
+		if !ok || !identity.HasRole(role) {
+			abortError(c, http.StatusForbidden, domain.ErrCodeForbidden, role+" role required")
+			return
+		}
+		c.Next()
+	}
+}
+
+func setIdentity(c *gin.Context, identity domain.Identity) {
+	c.Set(identityKey, identity)
+	c.Request = c.Request.WithContext(services.WithIdentity(c.Request.Context(), identity))
+}
+
+func identityFrom(c *gin.Context) (domain.Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return domain.Identity{}, false
+	}
+	identity, ok := value.(domain.Identity)
+	return identity, ok
+}
+
+// userID is the authenticated caller; request bodies never choose it
+func userID(c *gin.Context) string {
+	identity, _ := identityFrom(c)
+	return identity.UserID
+}
+
+// tenantID is the caller's workspace, "" outside any tenant
+func tenantID(c *gin.Context) string {
+	identity, _ := identityFrom(c)
+	return identity.TenantID
+}
@@ -0,0 +1,287 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/quota"
+	"geminizer-enterprise/internal/webhooks"
+)
+
+// Batch item outcomes, as counted by BatchSummary
+const (
+	BatchPassed         = "passed"
+	BatchSafetyRejected = "safety_rejected"
+	BatchQAFailed       = "qa_failed"
+	BatchQuotaExceeded  = "quota_exceeded"
+	BatchFailed         = "failed"
+)
+
+// BatchDefaults are applied to every item before the item's own fields,
+// so an item only needs to spell out what differs
+type BatchDefaults struct {
+	Tier     services.Tier            `json:"tier,omitempty" validate:"enum=tier"`
+	Style    string                   `json:"style,omitempty" validate:"enum=style"`
+	Filter   string                   `json:"filter,omitempty" validate:"enum=filter"`
+	ShotType string                   `json:"shot_type,omitempty" validate:"enum=shot_type"`
+	Mood     string                   `json:"mood,omitempty" validate:"enum=mood"`
+	Options  domain.GenerationOptions `json:"options"`
+	Explain  bool                     `json:"explain,omitempty"`
+	// CustomStyle names one of the caller's workspace styles
+	CustomStyle string `json:"custom_style,omitempty" validate:"max=64"`
+	// Stages replaces the tier's stage list
+	Stages []string `json:"stages,omitempty" validate:"max=20,enum=stage"`
+	// Profile runs a configured pipeline profile instead of the tier
+	Profile string `json:"profile,omitempty" validate:"max=64"`
+	// Provider names the image backend that draws; empty uses the default
+	Provider       string `json:"provider,omitempty" validate:"max=64"`
+	NegativePrompt string `json:"negative_prompt,omitempty" validate:"max=2000"`
+	AspectRatio    string `json:"aspect_ratio,omitempty" validate:"enum=aspect_ratio"`
+	// DryRun runs every analysis, review and check but draws no image
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type BatchRequest struct {
+	Defaults BatchDefaults          `json:"defaults"`
+	Items    []services.TierRequest `json:"items" validate:"required,min=1"`
+}
+
+// BatchItem is the outcome of one item; exactly one of Result and Error is set
+type BatchItem struct {
+	Index  int                  `json:"index"`
+	Status string               `json:"status"`
+	Result *services.TierResult `json:"result,omitempty"`
+	Error  *ErrorResponse       `json:"error,omitempty"`
+}
+
+type BatchSummary struct {
+	Total          int `json:"total"`
+	Passed         int `json:"passed"`
+	SafetyRejected int `json:"safety_rejected"`
+	QAFailed       int `json:"qa_failed"`
+	QuotaExceeded  int `json:"quota_exceeded"`
+	Failed         int `json:"failed"`
+}
+
+type BatchResponse struct {
+	Items          []BatchItem  `json:"items"`
+	Summary        BatchSummary `json:"summary"`
+	ProcessingTime string       `json:"processing_time"`
+}
+
+type BatchConfig struct {
+	Concurrency int
+	MaxItems    int
+}
+
+// BatchHandler runs many generations in one request. Each item is charged
+// and checked exactly as POST /generate would be; a failed item does not
+// fail the batch.
+type BatchHandler struct {
+	graph       *services.ServiceGraph
+	limiter     *quota.Limiter
+	concurrency int
+	maxItems    int
+}
+
+func NewBatchHandler(graph *services.ServiceGraph, limiter *quota.Limiter, config BatchConfig) *BatchHandler {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.MaxItems < 1 {
+		config.MaxItems = 1
+	}
+
+	return &BatchHandler{
+		graph:       graph,
+		limiter:     limiter,
+		concurrency: config.Concurrency,
+		maxItems:    config.MaxItems,
+	}
+}
+
+func RegisterBatchRoutes(groups Groups, h *BatchHandler) {
+	groups.Batch.POST("/generate/batch", h.GenerateBatch)
+}
+
+// GenerateBatch answers 200 whenever the batch itself was valid; the
+// summary and the per-item statuses say how each generation went
+func (h *BatchHandler) GenerateBatch(c *gin.Context) {
+	requests, ok := h.bindBatch(c)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	ctx := c.Request.Context()
+	subject := quotaSubject(c)
+	chargedAt := time.Now()
+	items := make([]BatchItem, len(requests))
+
+	// Charge in submission order so a batch larger than the remaining
+	// quota runs its leading items rather than an arbitrary subset. Dry
+	// runs draw no image and are not charged.
+	var admitted []services.BatchItem
+	charged := make([]bool, len(requests))
+	for i, request := range requests {
+		items[i].Index = i
+		if !request.DryRun {
+			if usage, err := h.charge(ctx, subject); err != nil {
+				items[i].Status, items[i].Error = quotaItemError(usage, err)
+				continue
+			}
+			charged[i] = true
+		}
+
+		// Overwritten by the result; left as is if the caller goes away first
+		items[i].Status = BatchFailed
+		items[i].Error = &ErrorResponse{Error: "not run: request cancelled", Code: domain.ErrCodeUnavailable}
+		admitted = append(admitted, services.BatchItem{ID: strconv.Itoa(i), Request: request})
+	}
+
+	h.graph.GenerateBatch(ctx, admitted, h.concurrency, func(result services.BatchResult) {
+		index, _ := strconv.Atoi(result.ID)
+		items[index] = batchItem(index, result)
+	})
+
+	// Charged items that failed or never ran give their charge back
+	refunds := 0
+	for _, item := range admitted {
+		if index, _ := strconv.Atoi(item.ID); charged[index] && items[index].Status != BatchPassed {
+			refunds++
+		}
+	}
+	h.refund(ctx, subject, refunds, chargedAt)
+
+	c.JSON(http.StatusOK, BatchResponse{
+		Items:          items,
+		Summary:        summarize(items),
+		ProcessingTime: time.Since(start).String(),
+	})
+}
+
+// bindBatch validates the batch and returns one TierRequest per item with
+// the defaults applied. Defaults are merged at the JSON level, so an item
+// that sets one option keeps the other default options.
+func (h *BatchHandler) bindBatch(c *gin.Context) ([]services.TierRequest, bool) {
+	var batch BatchRequest
+	if !bindJSON(c, &batch) {
+		return nil, false
+	}
+	if len(batch.Items) > h.maxItems {
+		respondFieldError(c, "items", "max_items", fmt.Sprintf("must have at most %d items", h.maxItems))
+		return nil, false
+	}
+
+	// bindJSON leaves the body readable; decode it again keeping items raw
+	var raw struct {
+		Defaults json.RawMessage   `json:"defaults"`
+		Items    []json.RawMessage `json:"items"`
+	}
+	body, _ := io.ReadAll(c.Request.Body)
+	json.Unmarshal(body, &raw)
+
+	requests := make([]services.TierRequest, len(raw.Items))
+	for i, item := range raw.Items {
+		request := &requests[i]
+		if len(raw.Defaults) > 0 {
+			json.Unmarshal(raw.Defaults, request)
+		}
+		json.Unmarshal(item, request)
+
+		if strings.TrimSpace(request.Prompt) == "" {
+			respondFieldError(c, fmt.Sprintf("items[%d].prompt", i), "required", "is required")
+			return nil, false
+		}
+		if request.Tier == "" {
+			request.Tier = services.TierEnhanced
+		}
+		if request.WebhookURL != "" {
+			if err := webhooks.ValidateURL(request.WebhookURL); err != nil {
+				respondFieldError(c, fmt.Sprintf("items[%d].webhook_url", i), "invalid_url", err.Error())
+				return nil, false
+			}
+		}
+	}
+
+	return requests, true
+}
+
+// charge takes one generation from the caller's quota, as the Quota
+// middleware does for a single request
+func (h *BatchHandler) charge(ctx context.Context, subject quota.Subject) (*quota.Usage, error) {
+	if h.limiter == nil {
+		return nil, nil
+	}
+	return h.limiter.Consume(ctx, subject, 1)
+}
+
+// refund gives back the charge of n items that did not generate
+func (h *BatchHandler) refund(ctx context.Context, subject quota.Subject, n int, chargedAt time.Time) {
+	if h.limiter == nil || n == 0 {
+		return
+	}
+	h.limiter.Refund(ctx, subject, n, chargedAt)
+}
+
+// quotaItemError mirrors abortQuota for a single item
+func quotaItemError(usage *quota.Usage, err error) (string, *ErrorResponse) {
+	var appErr *domain.AppError
+	if !errors.As(err, &appErr) || appErr.Code != domain.ErrCodeQuotaExceeded {
+		return BatchFailed, &ErrorResponse{Error: "quota store unavailable", Code: domain.ErrCodeUnavailable}
+	}
+	return BatchQuotaExceeded, &ErrorResponse{
+		Error:   appErr.Message,
+		Code:    domain.ErrCodeQuotaExceeded,
+		Details: gin.H{"quota": usage},
+	}
+}
+
+func batchItem(index int, result services.BatchResult) BatchItem {
+	item := BatchItem{Index: index, Status: BatchPassed, Result: result.Result}
+	if result.Error == "" {
+		return item
+	}
+
+	item.Status = BatchFailed
+	item.Error = &ErrorResponse{Error: result.Error, Code: result.Code}
+	switch result.Code {
+	case domain.ErrCodeSafetyRejected:
+		item.Status = BatchSafetyRejected
+	case domain.ErrCodeQAFailed:
+		item.Status = BatchQAFailed
+	case "":
+		item.Error.Code = domain.ErrCodeInternal
+	}
+	return item
+}
+
+func summarize(items []BatchItem) BatchSummary {
+	summary := BatchSummary{Total: len(items)}
+	for _, item := range items {
+		switch item.Status {
+		case BatchPassed:
+			summary.Passed++
+		case BatchSafetyRejected:
+			summary.SafetyRejected++
+		case BatchQAFailed:
+			summary.QAFailed++
+		case BatchQuotaExceeded:
+			summary.QuotaExceeded++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
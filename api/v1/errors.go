@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/openapi"
+)
+
+// maxBodyBytes bounds a JSON request body; a full batch of long prompts
+// fits well inside it
+const maxBodyBytes = 8 << 20
+
+// ErrorResponse is the body of every v1 error. Code is one of the
+// domain.ErrCode* values; Fields is set for VALIDATION_FAILED.
+type ErrorResponse struct {
+	Error   string               `json:"error"`
+	Code    string               `json:"code"`
+	Fields  []openapi.FieldError `json:"fields,omitempty"`
+	Details interface{}          `json:"details,omitempty"`
+}
+
+func respondError(c *gin.Context, status int, code string, message string) {
+	c.JSON(status, ErrorResponse{Error: message, Code: code})
+}
+
+func abortError(c *gin.Context, status int, code string, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Error: message, Code: code})
+}
+
+// respondServiceError reports an error from the service graph with the
+// status its AppError code implies
+func respondServiceError(c *gin.Context, err error) {
+	c.JSON(serviceErrorStatus(err), serviceError(err))
+}
+
+func serviceError(err error) ErrorResponse {
+	code := domain.ErrorCode(err)
+	if code == "" {
+		code = domain.ErrCodeInternal
+	}
+	return ErrorResponse{Error: err.Error(), Code: code}
+}
+
+func serviceErrorStatus(err error) int {
+	switch domain.ErrorCode(err) {
+	case domain.ErrCodeSafetyRejected, domain.ErrCodeQAFailed:
+		return http.StatusUnprocessableEntity
+	case domain.ErrCodeQuotaExceeded, domain.ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+	case domain.ErrCodeInvalidRequest, domain.ErrCodeValidationFailed:
+		return http.StatusBadRequest
+	case domain.ErrCodeForbidden:
+		return http.StatusForbidden
+	case domain.ErrCodeNotFound:
+		return http.StatusNotFound
+	case domain.ErrCodeTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case domain.ErrCodeUnavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// readBody reads at most maxBodyBytes of the request body and puts it back
+// for the next reader. On failure it aborts with a 413 or 400 and returns
+// false.
+func readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		abortError(c, http.StatusRequestEntityTooLarge, domain.ErrCodeTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBodyBytes))
+		return nil, false
+	}
+	if err != nil {
+		abortError(c, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "Invalid request")
+		return nil, false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true
+}
+
+// ValidateRequest checks req against the schema published for its type,
+// for transports such as gRPC that decode requests themselves
+func ValidateRequest(req interface{}) []openapi.FieldError {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return []openapi.FieldError{{Code: "invalid_json", Message: err.Error()}}
+	}
+
+	spec := apiSpec()
+	return spec.generator.Validate(spec.generator.Schema(req), body)
+}
+
+// bindJSON checks the body against the published schema for req's type
+// and then decodes it into req. On failure it writes a 400 with every
+// field error, or a 413 for an oversized body, and returns false.
+func bindJSON(c *gin.Context, req interface{}) bool {
+	body, ok := readBody(c)
+	if !ok {
+		return false
+	}
+
+	spec := apiSpec()
+	if fields := spec.generator.Validate(spec.generator.Schema(req), body); len(fields) > 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:  "request validation failed",
+			Code:   domain.ErrCodeValidationFailed,
+			Fields: fields,
+		})
+		return false
+	}
+
+	if err := json.Unmarshal(body, req); err != nil {
+		respondError(c, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "Invalid request")
+		return false
+	}
+	return true
+}
+
+// respondFieldError is a VALIDATION_FAILED response for a check the
+// schema cannot express
+func respondFieldError(c *gin.Context, field string, code string, message string) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:  "request validation failed",
+		Code:   domain.ErrCodeValidationFailed,
+		Fields: []openapi.FieldError{{Field: field, Code: code, Message: message}},
+	})
+}
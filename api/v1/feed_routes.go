@@ -0,0 +1,292 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/feed"
+)
+
+const (
+	feedWriteWait  = 10 * time.Second
+	feedPongWait   = 60 * time.Second
+	feedPingPeriod = feedPongWait * 9 / 10
+	feedReadLimit  = 4096
+)
+
+// FeedHandler serves the live operations feed over WebSocket
+type FeedHandler struct {
+	hub      *feed.Hub
+	watcher  *feed.Watcher
+	upgrader websocket.Upgrader
+}
+
+func NewFeedHandler(hub *feed.Hub, watcher *feed.Watcher) *FeedHandler {
+	return &FeedHandler{
+		hub:     hub,
+		watcher: watcher,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+		},
+	}
+}
+
+func RegisterFeedRoutes(groups Groups, h *FeedHandler) {
+	groups.User.GET("/ws/feed", h.Feed)
+}
+
+// feedCommand is a message from the client:
+//
+//	{"action": "subscribe", "topics": ["alerts"]}
+//	{"action": "unsubscribe", "topics": ["status"]}
+//	{"action": "resync"}
+type feedCommand struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics,omitempty"`
+}
+
+// feedNotice is a message about the connection rather than the system
+type feedNotice struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics,omitempty"`
+	Count  int      `json:"count,omitempty"`
+	Error  string   `json:"error,omitempty"`
+	Code   string   `json:"code,omitempty"`
+}
+
+// Feed upgrades to a WebSocket that carries the ?topics= (comma separated,
+// default every topic the caller may see). Each status and health topic
+// starts with a snapshot; after a "dropped" notice the client should send
+// "resync" to get fresh snapshots.
+func (h *FeedHandler) Feed(c *gin.Context) {
+	identity, _ := identityFrom(c)
+
+	var requested []string
+	if raw := c.Query("topics"); raw != "" {
+		requested = strings.Split(raw, ",")
+	}
+	topics, code, err := feedTopics(identity, requested)
+	if err != nil {
+		if code == domain.ErrCodeForbidden {
+			respondError(c, http.StatusForbidden, code, err.Error())
+		} else {
+			respondFieldError(c, "topics", "enum", err.Error())
+		}
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written the HTTP error
+		return
+	}
+	defer conn.Close()
+
+	sub := h.hub.Subscribe(topics)
+	defer sub.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	commands := make(chan feedCommand)
+	go readFeedCommands(conn, commands, done)
+
+	h.serve(conn, sub, identity, commands)
+}
+
+func (h *FeedHandler) serve(conn *websocket.Conn, sub *feed.Subscription, identity domain.Identity, commands <-chan feedCommand) {
+	send := func(message interface{}) error {
+		conn.SetWriteDeadline(time.Now().Add(feedWriteWait))
+		return conn.WriteJSON(message)
+	}
+
+	if send(feedNotice{Type: "subscribed", Topics: sub.Topics()}) != nil || h.sendSnapshots(send, sub.Topics()) != nil {
+		return
+	}
+
+	ping := time.NewTicker(feedPingPeriod)
+	defer ping.Stop()
+
+	for {
+		var err error
+
+		select {
+		case event := <-sub.Events():
+			if dropped := sub.Dropped(); dropped > 0 {
+				err = send(feedNotice{Type: "dropped", Count: dropped})
+			}
+			if err == nil {
+				err = send(event)
+			}
+
+		case <-sub.Done():
+			closeFeed(conn, sub.Err())
+			return
+
+		case command, ok := <-commands:
+			if !ok {
+				return
+			}
+			err = h.handleCommand(send, sub, identity, command)
+
+		case <-ping.C:
+			if dropped := sub.Dropped(); dropped > 0 {
+				err = send(feedNotice{Type: "dropped", Count: dropped})
+			}
+			if err == nil {
+				err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteWait))
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *FeedHandler) handleCommand(send func(interface{}) error, sub *feed.Subscription, identity domain.Identity, command feedCommand) error {
+	switch command.Action {
+	case "subscribe", "unsubscribe":
+		topics, code, err := feedTopics(identity, command.Topics)
+		if err != nil {
+			return send(feedNotice{Type: "error", Error: err.Error(), Code: code})
+		}
+
+		current := sub.Topics()
+		var added []string
+		if command.Action == "subscribe" {
+			for _, topic := range topics {
+				if !containsString(current, topic) {
+					current = append(current, topic)
+					added = append(added, topic)
+				}
+			}
+		} else {
+			kept := current[:0]
+			for _, topic := range current {
+				if !containsString(topics, topic) {
+					kept = append(kept, topic)
+				}
+			}
+			current = kept
+		}
+		sub.SetTopics(current)
+
+		if err := send(feedNotice{Type: "subscribed", Topics: sub.Topics()}); err != nil {
+			return err
+		}
+		return h.sendSnapshots(send, added)
+
+	case "resync":
+		return h.sendSnapshots(send, sub.Topics())
+	}
+
+	return send(feedNotice{
+		Type:  "error",
+		Error: "action must be subscribe, unsubscribe or resync",
+		Code:  domain.ErrCodeInvalidRequest,
+	})
+}
+
+func (h *FeedHandler) sendSnapshots(send func(interface{}) error, topics []string) error {
+	for _, topic := range topics {
+		if snapshot, ok := h.watcher.Snapshot(topic); ok {
+			if err := send(snapshot); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readFeedCommands owns the read side of the connection: it answers pongs,
+// enforces the read deadline and hands commands to the writer
+func readFeedCommands(conn *websocket.Conn, commands chan<- feedCommand, done <-chan struct{}) {
+	defer close(commands)
+
+	conn.SetReadLimit(feedReadLimit)
+	conn.SetReadDeadline(time.Now().Add(feedPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(feedPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var command feedCommand
+		if err := json.Unmarshal(data, &command); err != nil {
+			command = feedCommand{}
+		}
+
+		select {
+		case commands <- command:
+		case <-done:
+			return
+		}
+	}
+}
+
+// feedTopics checks requested topics against what the caller may see; no
+// topics means all of those
+func feedTopics(identity domain.Identity, requested []string) ([]string, string, error) {
+	admin := identity.HasRole(domain.RoleAdmin)
+
+	if len(requested) == 0 {
+		var topics []string
+		for _, topic := range feed.Topics {
+			if admin || !feed.AdminTopic(topic) {
+				topics = append(topics, topic)
+			}
+		}
+		return topics, "", nil
+	}
+
+	topics := make([]string, 0, len(requested))
+	for _, topic := range requested {
+		topic = strings.TrimSpace(topic)
+		if !feed.ValidTopic(topic) {
+			return nil, domain.ErrCodeValidationFailed, errors.New("unknown topic " + topic + " (expected " + strings.Join(feed.Topics, ", ") + ")")
+		}
+		if feed.AdminTopic(topic) && !admin {
+			return nil, domain.ErrCodeForbidden, errors.New(domain.RoleAdmin + " role required for topic " + topic)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, "", nil
+}
+
+// closeFeed tells the client why the server ended the subscription
+func closeFeed(conn *websocket.Conn, err error) {
+	code := websocket.CloseNormalClosure
+	switch {
+	case errors.Is(err, feed.ErrSlowConsumer):
+		code = websocket.CloseTryAgainLater
+	case errors.Is(err, feed.ErrHubClosed):
+		code = websocket.CloseGoingAway
+	}
+
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(feedWriteWait))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
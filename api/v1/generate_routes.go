@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+// Generate runs a prompt through the tier named in the request body
+func (h *ImageHandler) Generate(c *gin.Context) {
+	var request services.TierRequest
+	if !bindTierRequest(c, &request) {
+		return
+	}
+
+	result, err := h.graph.Generate(c.Request.Context(), request)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListPipelines describes what a request may run instead of its tier: the
+// builtin stages, each tier's own stage list and the configured profiles
+func (h *ImageHandler) ListPipelines(c *gin.Context) {
+	tiers := make(map[string][]string, len(services.Tiers))
+	for _, tier := range services.Tiers {
+		tiers[string(tier)] = services.TierStages(tier)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"stages":   services.StageNames,
+		"tiers":    tiers,
+		"profiles": h.graph.Profiles(),
+	})
+}
+
+// ListProviders describes the image backends a request can select
+func (h *ImageHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.graph.ImageProviders()})
+}
+
+// bindTierRequest binds and validates a TierRequest and fills in the
+// default tier; it writes the error response itself
+func bindTierRequest(c *gin.Context, request *services.TierRequest) bool {
+	if !bindJSON(c, request) {
+		return false
+	}
+
+	if strings.TrimSpace(request.Prompt) == "" {
+		respondFieldError(c, "prompt", "required", "is required")
+		return false
+	}
+	if request.Tier == "" {
+		request.Tier = services.TierEnhanced
+	}
+	if request.DryRun {
+		c.Set(noChargeKey, true)
+	}
+
+	return validWebhookURL(c, request.WebhookURL)
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/demo"
+)
+
+// ImageHandler serves the v1 API on top of one shared service graph
+type ImageHandler struct {
+	graph            *services.ServiceGraph
+	aiManager        *management.ManagerAgent
+	healthMonitor    *management.HealthMonitor
+	safetyAnalyzer   *ai.AdvancedSafetyAnalyzer
+	explicitDetector *ai.ExplicitDetector
+	moderation       *ai.ModerationHistory
+	recovery         *ai.RecoveryEngine
+	consciousUI      *ai.ConsciousUI
+	promptEnhancer   *ai.PromptEnhancer
+	demos            *demo.Runner
+	startTime        time.Time
+}
+
+func NewImageHandler(graph *services.ServiceGraph, aiManager *management.ManagerAgent, healthMonitor *management.HealthMonitor, moderation *ai.ModerationHistory, demos *demo.Runner) *ImageHandler {
+	return &ImageHandler{
+		graph:            graph,
+		aiManager:        aiManager,
+		healthMonitor:    healthMonitor,
+		safetyAnalyzer:   ai.NewAdvancedSafetyAnalyzer(),
+		explicitDetector: ai.NewExplicitDetector(),
+		moderation:       moderation,
+		recovery:         ai.NewRecoveryEngine(),
+		consciousUI:      ai.NewConsciousUI(),
+		promptEnhancer:   ai.NewPromptEnhancer(),
+		demos:            demos,
+		startTime:        time.Now(),
+	}
+}
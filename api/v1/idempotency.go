@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/idempotency"
+)
+
+const (
+	idempotencyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// recordingWriter keeps a copy of everything the handler writes
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a request repeats an
+// Idempotency-Key, and rejects a key reused with a different body. Only
+// final JSON responses are kept; anything else (a server error, a
+// transient refusal such as a 429, a stream or a handler panic) releases
+// the key so the client can retry it.
+func Idempotency(store idempotency.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			abortError(c, http.StatusBadRequest, domain.ErrCodeInvalidRequest, idempotencyHeader+" is too long")
+			return
+		}
+
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+
+		// Keys are per caller so two users can never collide
+		storeKey := userID(c) + ":" + key
+		hash := requestHash(c.Request, body)
+
+		existing, reserved, err := store.Reserve(c.Request.Context(), storeKey, &idempotency.Record{
+			RequestHash: hash,
+			CreatedAt:   time.Now().UTC(),
+		}, ttl)
+		if err != nil {
+			abortError(c, http.StatusServiceUnavailable, domain.ErrCodeUnavailable, "idempotency store unavailable")
+			return
+		}
+
+		if !reserved {
+			replay(c, existing, hash)
+			return
+		}
+
+		ctx := context.WithoutCancel(c.Request.Context())
+		completed := false
+		defer func() {
+			// Also runs while a handler panic unwinds to the recovery
+			// middleware, so the key never stays pending until it expires
+			if !completed {
+				store.Release(ctx, storeKey)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if !storable(status) || !strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+			return
+		}
+
+		completed = true
+		store.Complete(ctx, storeKey, &idempotency.Record{
+			RequestHash: hash,
+			Completed:   true,
+			Status:      status,
+			Header:      http.Header{"Content-Type": writer.Header().Values("Content-Type")},
+			Body:        writer.body.Bytes(),
+			CreatedAt:   time.Now().UTC(),
+		}, ttl)
+	}
+}
+
+// storable reports whether a response status is final for its request. A
+// server error, a timeout or a rate or quota refusal may pass on retry.
+func storable(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+func replay(c *gin.Context, record *idempotency.Record, hash string) {
+	switch {
+	case record.RequestHash != hash:
+		abortError(c, http.StatusConflict, "IDEMPOTENCY_KEY_REUSED", idempotencyHeader+" was already used with a different request")
+	case !record.Completed:
+		c.Header("Retry-After", "5")
+		abortError(c, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS", "a request with this "+idempotencyHeader+" is still in progress")
+	default:
+		for name, values := range record.Header {
+			for _, value := range values {
+				c.Writer.Header().Add(name, value)
+			}
+		}
+		c.Header("Idempotent-Replayed", "true")
+		c.Writer.WriteHeader(record.Status)
+		c.Writer.Write(record.Body)
+		c.Abort()
+	}
+}
+
+// requestHash covers the route as well as the body, so one key cannot be
+// replayed against a different endpoint
+func requestHash(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
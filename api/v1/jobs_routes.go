@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/jobs"
+)
+
+// JobHandler exposes asynchronous generation jobs
+type JobHandler struct {
+	manager *jobs.Manager
+}
+
+func NewJobHandler(manager *jobs.Manager) *JobHandler {
+	return &JobHandler{manager: manager}
+}
+
+func RegisterJobRoutes(groups Groups, h *JobHandler) {
+	groups.Generation.POST("/jobs", h.CreateJob)
+	groups.Generation.POST("/comics", h.CreateComicJob)
+	groups.User.GET("/jobs/:id", h.GetJob)
+	groups.User.DELETE("/jobs/:id", h.CancelJob)
+}
+
+// CreateJob queues a generation and returns immediately with its ID
+func (h *JobHandler) CreateJob(c *gin.Context) {
+	var request services.TierRequest
+	if !bindTierRequest(c, &request) {
+		return
+	}
+
+	job, err := h.manager.Submit(c.Request.Context(), request)
+	h.accepted(c, job, err)
+}
+
+// CreateComicJob runs ComicBookEngine.GenerateComicFromOutline as a job;
+// set webhook_url to be told when it finishes instead of polling
+func (h *JobHandler) CreateComicJob(c *gin.Context) {
+	var request services.ComicRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+	if !validWebhookURL(c, request.WebhookURL) {
+		return
+	}
+
+	job, err := h.manager.SubmitComic(c.Request.Context(), request)
+	h.accepted(c, job, err)
+}
+
+func (h *JobHandler) accepted(c *gin.Context, job *jobs.Job, err error) {
+	if errors.Is(err, jobs.ErrQueueFull) {
+		c.Header("Retry-After", "30")
+		respondError(c, http.StatusServiceUnavailable, domain.ErrCodeUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, domain.ErrCodeInternal, err.Error())
+		return
+	}
+
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id": job.ID,
+		"kind":   job.Kind,
+		"status": job.Status,
+	})
+}
+
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, ok := h.ownedJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	if _, ok := h.ownedJob(c); !ok {
+		return
+	}
+
+	job, err := h.manager.Cancel(c.Request.Context(), c.Param("id"))
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, err.Error())
+	case errors.Is(err, jobs.ErrJobFinished):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   err.Error(),
+			Code:    domain.ErrCodeConflict,
+			Details: gin.H{"status": job.Status},
+		})
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, domain.ErrCodeInternal, err.Error())
+	default:
+		c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "status": job.Status})
+	}
+}
+
+// ownedJob loads the job named in the path and writes the error response
+// itself. Other users' jobs are reported as missing rather than forbidden.
+func (h *JobHandler) ownedJob(c *gin.Context) (*jobs.Job, bool) {
+	job, err := h.manager.Get(c.Request.Context(), c.Param("id"))
+	if err != nil && !errors.Is(err, jobs.ErrJobNotFound) {
+		respondError(c, http.StatusInternalServerError, domain.ErrCodeInternal, err.Error())
+		return nil, false
+	}
+
+	identity, _ := identityFrom(c)
+	if err != nil || (job.Owner() != identity.UserID && !identity.HasRole(domain.RoleAdmin)) {
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, jobs.ErrJobNotFound.Error())
+		return nil, false
+	}
+
+	return job, true
+}
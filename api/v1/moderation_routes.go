@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// shutdownRequest records why a user was shut down or let back in
+type shutdownRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=500"`
+}
+
+// ShutdownUser runs the recovery engine's emergency shutdown for a user
+// (incident log, high-security mode, admin notification) and blocks them
+// from generating on this server. The block is kept in the moderation
+// history, so a persisted log restores it.
+func (h *ImageHandler) ShutdownUser(c *gin.Context) {
+	var request shutdownRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	user := c.Param("user_id")
+	h.recovery.EmergencyShutdown(user, request.Reason)
+	h.moderation.ShutDownUser(user, request.Reason)
+
+	c.JSON(http.StatusOK, gin.H{"user_id": user, "shut_down": true, "timestamp": time.Now().UTC()})
+}
+
+// RestoreUser lifts a user's shutdown
+func (h *ImageHandler) RestoreUser(c *gin.Context) {
+	var request shutdownRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	user := c.Param("user_id")
+	h.moderation.LiftShutdown(user, request.Reason)
+
+	c.JSON(http.StatusOK, gin.H{"user_id": user, "shut_down": false, "timestamp": time.Now().UTC()})
+}
+
+// ModerationViolations lists violations newest first, filtered by type,
+// tenant and age. Counts tally the filtered violations before the limit
+// is applied.
+func (h *ImageHandler) ModerationViolations(c *gin.Context) {
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		age, err := time.ParseDuration(raw)
+		if err != nil || age <= 0 {
+			respondError(c, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "since must be a positive duration such as 24h")
+			return
+		}
+		since = time.Now().Add(-age)
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			respondError(c, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	violations := h.moderation.GetTenantViolations(c.Query("tenant"), c.Query("type"), since)
+	counts := make(map[string]int)
+	for _, violation := range violations {
+		counts[violation.Type]++
+	}
+	total := len(violations)
+	if limit > 0 && total > limit {
+		violations = violations[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"violations": violations, "counts": counts, "total": total, "timestamp": time.Now().UTC()})
+}
@@ -0,0 +1,332 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/demo"
+	"geminizer-enterprise/internal/jobs"
+	"geminizer-enterprise/internal/openapi"
+	"geminizer-enterprise/internal/quota"
+	"geminizer-enterprise/internal/webhooks"
+)
+
+// access is who may call an operation; it decides its security section
+type access int
+
+const (
+	accessPublic access = iota
+	accessUser
+	accessAdmin
+)
+
+// operation documents one route. The request type is also what bindJSON
+// validates against, so every JSON body a handler binds must appear here.
+type operation struct {
+	method   string
+	path     string
+	id       string
+	summary  string
+	tag      string
+	access   access
+	query    []string
+	request  interface{}
+	status   int
+	response interface{}
+	stream   bool
+}
+
+type expertCommandRequest struct {
+	Command   string `json:"command" validate:"required,min=1,max=4000"`
+	Expertise string `json:"expertise,omitempty" validate:"enum=expertise"`
+}
+
+var acceptedJob = gin.H{"job_id": "", "kind": jobs.KindGeneration, "status": jobs.StatusQueued}
+
+var operations = []operation{
+	{method: "GET", path: "/health", id: "health", summary: "Liveness and overall health", tag: "system", access: accessPublic, status: http.StatusOK,
+		response: gin.H{"status": "", "uptime": "", "timestamp": time.Time{}}},
+	{method: "GET", path: "/openapi.json", id: "getOpenAPI", summary: "This document", tag: "system", access: accessPublic, status: http.StatusOK,
+		response: &openapi.Schema{Type: "object"}},
+
+	{method: "POST", path: "/generate", id: "generate", summary: "Generate an image through the requested tier", tag: "generation", access: accessUser,
+		request: &services.TierRequest{}, status: http.StatusOK, response: &services.TierResult{}},
+	{method: "POST", path: "/generate/stream", id: "generateStream", summary: "Generate with progress as Server-Sent Events", tag: "generation", access: accessUser,
+		request: &services.TierRequest{}, status: http.StatusOK, stream: true},
+	{method: "POST", path: "/generate/batch", id: "generateBatch", summary: "Generate many prompts with shared defaults; items succeed or fail independently", tag: "generation", access: accessUser,
+		request: &BatchRequest{}, status: http.StatusOK, response: &BatchResponse{}},
+	{method: "GET", path: "/pipelines", id: "listPipelines", summary: "Stages, tier stage lists and profiles a request can run", tag: "generation", access: accessUser, status: http.StatusOK,
+		response: gin.H{"stages": []string{}, "tiers": map[string][]string{}, "profiles": []services.Profile{}}},
+	{method: "GET", path: "/providers", id: "listProviders", summary: "Image providers a request can select and their capabilities", tag: "generation", access: accessUser, status: http.StatusOK,
+		response: gin.H{"providers": []services.ImageProviderInfo{}}},
+
+	{method: "POST", path: "/safety/check", id: "safetyCheck", summary: "Check a prompt against the safety analyzers", tag: "analysis", access: accessUser,
+		request: &promptRequest{}, status: http.StatusOK,
+		response: gin.H{"is_safe": false, "issues": []string{}, "recovery_suggestions": []string{}, "timestamp": time.Time{}}},
+	{method: "POST", path: "/expert/command", id: "expertCommand", summary: "Interpret a directive with ConsciousUI", tag: "analysis", access: accessUser,
+		request: &expertCommandRequest{}, status: http.StatusOK,
+		response: gin.H{"message": "", "suggestions": []string{}, "intent": "", "confidence": 0.0}},
+	{method: "POST", path: "/analyze/professional", id: "analyzeProfessional", summary: "Professional prompt analysis", tag: "analysis", access: accessUser,
+		request: &promptRequest{}, status: http.StatusOK,
+		response: gin.H{"analysis": nil, "enhanced_prompt": "", "quality_score": 0.0, "improvements": []string{}}},
+
+	{method: "GET", path: "/ai/status", id: "getAIStatus", summary: "Agent system status", tag: "ai", access: accessPublic, status: http.StatusOK,
+		response: gin.H{"status": "", "overall_health": 0.0, "average_performance": 0.0, "system_load": 0.0, "uptime": "",
+			"total_agents": 0, "agents_used": 0, "agents": nil, "timestamp": time.Time{}}},
+	{method: "GET", path: "/ai/agents/:id", id: "getAgentDetails", summary: "One agent's details", tag: "ai", access: accessAdmin, status: http.StatusOK,
+		response: gin.H{"agent": nil, "timestamp": time.Time{}}},
+	{method: "GET", path: "/ai/diagnostics", id: "systemDiagnostics", summary: "Health monitor diagnostics", tag: "ai", access: accessAdmin, status: http.StatusOK,
+		response: gin.H{"diagnostics": nil, "timestamp": time.Time{}}},
+
+	{method: "GET", path: "/admin/moderation", id: "listModerationViolations", summary: "Moderation violations, newest first, with counts by type", tag: "admin", access: accessAdmin,
+		query: []string{"type", "tenant", "since", "limit"}, status: http.StatusOK,
+		response: gin.H{"violations": []ai.ModerationViolation{}, "counts": map[string]int{}, "total": 0, "timestamp": time.Time{}}},
+	{method: "POST", path: "/admin/users/:user_id/shutdown", id: "shutdownUser", summary: "Block a user from generating", tag: "admin", access: accessAdmin,
+		request: &shutdownRequest{}, status: http.StatusOK, response: gin.H{"user_id": "", "shut_down": false, "timestamp": time.Time{}}},
+	{method: "DELETE", path: "/admin/users/:user_id/shutdown", id: "restoreUser", summary: "Lift a user's shutdown", tag: "admin", access: accessAdmin,
+		request: &shutdownRequest{}, status: http.StatusOK, response: gin.H{"user_id": "", "shut_down": false, "timestamp": time.Time{}}},
+
+	{method: "GET", path: "/demo/sample", id: "getSampleDemo", summary: "Run a demo scenario through the pipeline", tag: "demo", access: accessUser,
+		query: []string{"scenario"}, status: http.StatusOK,
+		response: gin.H{"scenario": "", "title": "", "tier": services.TierEnhanced, "original_input": "", "enhanced_prompt": "",
+			"image_url": "", "intent": "", "quality_score": 0.0, "agents": []string{}, "agents_used": 0,
+			"stages": []demo.StageTiming{}, "processing_time": "", "processing_time_ms": int64(0), "checks": []demo.Check{},
+			"passed": false, "ran_at": time.Time{}, "cached": false, "ai_system_status": nil}},
+	{method: "GET", path: "/demo/scenarios", id: "listDemoScenarios", summary: "The demo scenario catalog", tag: "demo", access: accessPublic, status: http.StatusOK,
+		response: gin.H{"scenarios": []*demo.Scenario{}, "default": ""}},
+	{method: "POST", path: "/demo/custom", id: "processCustomDemo", summary: "Run a prompt through the enhanced tier", tag: "demo", access: accessUser,
+		request: &promptRequest{}, status: http.StatusOK, response: &services.TierResult{}},
+
+	{method: "GET", path: "/ws/feed", id: "liveFeed", summary: "WebSocket feed of status deltas, health changes, alerts and violations", tag: "ai", access: accessUser,
+		query: []string{"topics", "access_token"}, status: http.StatusSwitchingProtocols},
+
+	{method: "POST", path: "/jobs", id: "createJob", summary: "Queue a generation", tag: "jobs", access: accessUser,
+		request: &services.TierRequest{}, status: http.StatusAccepted, response: acceptedJob},
+	{method: "POST", path: "/comics", id: "createComicJob", summary: "Queue a comic from an outline", tag: "jobs", access: accessUser,
+		request: &services.ComicRequest{}, status: http.StatusAccepted, response: acceptedJob},
+	{method: "GET", path: "/jobs/:id", id: "getJob", summary: "Poll a job", tag: "jobs", access: accessUser, status: http.StatusOK,
+		response: &jobs.Job{}},
+	{method: "DELETE", path: "/jobs/:id", id: "cancelJob", summary: "Cancel a queued or running job", tag: "jobs", access: accessUser, status: http.StatusOK,
+		response: gin.H{"job_id": "", "status": jobs.StatusCancelled}},
+
+	{method: "GET", path: "/admin/webhooks/dead-letters", id: "listDeadLetters", summary: "Webhook deliveries that never succeeded", tag: "admin", access: accessAdmin, status: http.StatusOK,
+		response: gin.H{"dead_letters": []*webhooks.DeadLetter{}, "count": 0, "timestamp": time.Time{}}},
+	{method: "POST", path: "/admin/webhooks/dead-letters/:id/retry", id: "retryDeadLetter", summary: "Requeue a dead letter", tag: "admin", access: accessAdmin, status: http.StatusAccepted,
+		response: gin.H{"event_id": "", "status": ""}},
+	{method: "PUT", path: "/admin/webhooks/tenants/:id", id: "setTenantEndpoint", summary: "Set a tenant's webhook endpoint", tag: "admin", access: accessAdmin,
+		request: &webhooks.Endpoint{}, status: http.StatusOK, response: gin.H{"tenant_id": "", "url": ""}},
+	{method: "DELETE", path: "/admin/webhooks/tenants/:id", id: "deleteTenantEndpoint", summary: "Remove a tenant's webhook endpoint", tag: "admin", access: accessAdmin, status: http.StatusNoContent},
+
+	{method: "GET", path: "/admin/tenants", id: "listTenants", summary: "Every tenant workspace", tag: "admin", access: accessAdmin, status: http.StatusOK,
+		response: gin.H{"tenants": []*domain.Tenant{}, "count": 0, "timestamp": time.Time{}}},
+	{method: "POST", path: "/admin/tenants", id: "createTenant", summary: "Create a tenant workspace", tag: "admin", access: accessAdmin,
+		request: &tenantRequest{}, status: http.StatusCreated, response: &tenantResponse{}},
+	{method: "GET", path: "/admin/tenants/:id", id: "getTenant", summary: "A tenant and its users", tag: "admin", access: accessAdmin, status: http.StatusOK,
+		response: &tenantResponse{}},
+	{method: "PUT", path: "/admin/tenants/:id", id: "updateTenant", summary: "Replace a tenant's plan, styles, safety policy and pipelines", tag: "admin", access: accessAdmin,
+		request: &tenantSettings{}, status: http.StatusOK, response: &tenantResponse{}},
+	{method: "PUT", path: "/admin/tenants/:id/users/:user_id", id: "assignTenantUser", summary: "Move a user into a tenant", tag: "admin", access: accessAdmin, status: http.StatusOK,
+		response: gin.H{"tenant_id": "", "user_id": ""}},
+	{method: "DELETE", path: "/admin/tenants/:id/users/:user_id", id: "unassignTenantUser", summary: "Remove a user from a tenant", tag: "admin", access: accessAdmin, status: http.StatusNoContent},
+}
+
+// spec is the generated document and the generator whose schemas the
+// request validation uses
+type spec struct {
+	generator *openapi.Generator
+	document  *openapi.Document
+	body      []byte
+}
+
+var (
+	specOnce  sync.Once
+	specValue *spec
+)
+
+func apiSpec() *spec {
+	specOnce.Do(func() {
+		specValue = buildSpec()
+	})
+	return specValue
+}
+
+// specEnums are the closed vocabularies requests are checked against,
+// taken from the engines that interpret them
+func specEnums() map[string][]string {
+	tiers := make([]string, len(services.Tiers))
+	for i, tier := range services.Tiers {
+		tiers[i] = string(tier)
+	}
+	styles := ai.NewArtStyleEngine()
+	plans := make([]string, 0, len(quota.DefaultPlans))
+	for name := range quota.DefaultPlans {
+		plans = append(plans, name)
+	}
+	sort.Strings(plans)
+
+	return map[string][]string{
+		"tier":         tiers,
+		"style":        styles.Styles(),
+		"filter":       styles.Filters(),
+		"shot_type":    ai.ShotTypes,
+		"mood":         ai.Moods,
+		"expertise":    {"beginner", "intermediate", "expert"},
+		"plan":         plans,
+		"stage":        services.StageNames,
+		"aspect_ratio": domain.AspectRatios,
+	}
+}
+
+func buildSpec() *spec {
+	enums := specEnums()
+	g := openapi.NewGenerator(enums)
+	errorSchema := g.Schema(&ErrorResponse{})
+
+	document := &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info: openapi.Info{
+			Title:   "Geminizer Enterprise API",
+			Version: "1.0.0",
+		},
+		Servers: []openapi.Server{{URL: "/api/v1"}},
+		Paths:   make(map[string]*openapi.PathItem),
+	}
+
+	for _, op := range operations {
+		path, params := openAPIPath(op.path)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			document.Paths[path] = item
+		}
+		(*item)[strings.ToLower(op.method)] = buildOperation(g, op, params, errorSchema)
+	}
+
+	// Types we do not tag ourselves
+	if result, ok := g.Component("TierResult"); ok {
+		result.Properties["response"] = &openapi.Schema{OneOf: []*openapi.Schema{
+			g.Schema(&domain.EnhancedGenerationResponse{}),
+			g.Schema(&domain.FinalGenerationResponse{}),
+			g.Schema(&domain.EnterpriseResponse{}),
+			g.Schema(&domain.Enterprise3DResponse{}),
+			g.Schema(&domain.MasterResponse{}),
+		}}
+	}
+	if options, ok := g.Component("GenerationOptions"); ok {
+		for name, property := range options.Properties {
+			if strings.EqualFold(name, "style") {
+				property.Enum = enums["style"]
+			}
+		}
+	}
+
+	document.Components = openapi.Components{
+		Schemas: g.Components(),
+		SecuritySchemes: map[string]openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		panic("marshal openapi document: " + err.Error())
+	}
+
+	return &spec{generator: g, document: document, body: body}
+}
+
+func buildOperation(g *openapi.Generator, op operation, params []string, errorSchema *openapi.Schema) *openapi.Operation {
+	result := &openapi.Operation{
+		OperationID: op.id,
+		Summary:     op.summary,
+		Tags:        []string{op.tag},
+		Security:    []map[string][]string{},
+		Responses: map[string]*openapi.Response{
+			"default": {Description: "error", Content: jsonContent(errorSchema)},
+		},
+	}
+
+	switch op.access {
+	case accessUser:
+		result.Security = []map[string][]string{{"bearerAuth": {}}}
+	case accessAdmin:
+		result.Security = []map[string][]string{{"bearerAuth": {}}}
+		result.Summary += " (admin role)"
+	}
+
+	for _, name := range params {
+		result.Parameters = append(result.Parameters, openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string"},
+		})
+	}
+
+	for _, name := range op.query {
+		result.Parameters = append(result.Parameters, openapi.Parameter{
+			Name:   name,
+			In:     "query",
+			Schema: &openapi.Schema{Type: "string"},
+		})
+	}
+
+	if op.request != nil {
+		result.RequestBody = &openapi.RequestBody{Required: true, Content: jsonContent(g.Schema(op.request))}
+	}
+
+	response := &openapi.Response{Description: http.StatusText(op.status)}
+	switch {
+	case op.stream:
+		response.Content = map[string]openapi.MediaType{
+			"text/event-stream": {Schema: &openapi.Schema{Type: "string", Description: "progress events, then one result or error event"}},
+		}
+	case op.response != nil:
+		response.Content = jsonContent(responseSchema(g, op.response))
+	}
+	result.Responses[strconv.Itoa(op.status)] = response
+
+	return result
+}
+
+func responseSchema(g *openapi.Generator, response interface{}) *openapi.Schema {
+	switch v := response.(type) {
+	case *openapi.Schema:
+		return v
+	case gin.H:
+		return g.Object(v)
+	}
+	return g.Schema(response)
+}
+
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+// openAPIPath turns /jobs/:id into /jobs/{id} and returns the parameter names
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// OpenAPI serves the generated document
+func OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", apiSpec().body)
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/quota"
+)
+
+// RateLimit applies the caller's token buckets and sets the RateLimit-*
+// headers on every response
+func RateLimit(limiter *quota.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		decision, err := limiter.Allow(c.Request.Context(), quotaSubject(c))
+		if err != nil {
+			// Fail open: losing the limit store should not take the API down
+			c.Next()
+			return
+		}
+
+		if decision.Limit > 0 {
+			c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			c.Header("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+		}
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(decision.Reset)))
+			abortError(c, http.StatusTooManyRequests, domain.ErrCodeRateLimited, "rate limit exceeded")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// generationFailedKey marks a request whose generation failed after its
+// response had started, such as a stream, for the Quota middleware
+const generationFailedKey = "generation_failed"
+
+// noChargeKey marks a request that drew no new image, such as a dry run
+// or a cached demo, so the Quota middleware gives its charge back
+const noChargeKey = "no_charge"
+
+// Quota charges one generation against the caller's daily and monthly
+// quotas before the handler runs, and refunds it when the request fails:
+// an invalid, rejected or failed generation, a dry run or a cached demo
+// costs nothing.
+// The charge is also put on the request context, so a job accepted with a
+// 202 carries it and refunds it itself if the job does not succeed.
+func Quota(limiter *quota.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := quotaSubject(c)
+		chargedAt := time.Now()
+		usage, err := limiter.Consume(c.Request.Context(), subject, 1)
+		if err != nil {
+			abortQuota(c, usage, err)
+			return
+		}
+		charge := quota.Charge{Subject: subject, Units: 1, ChargedAt: chargedAt}
+		c.Request = c.Request.WithContext(quota.WithCharge(c.Request.Context(), charge))
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest || c.GetBool(generationFailedKey) || c.GetBool(noChargeKey) {
+			limiter.Refund(c.Request.Context(), subject, 1, chargedAt)
+		}
+	}
+}
+
+// abortQuota writes a QUOTA_EXCEEDED AppError, or a 503 when the quota
+// store itself failed; generations are not given away when it is down
+func abortQuota(c *gin.Context, usage *quota.Usage, err error) {
+	var appErr *domain.AppError
+	if !errors.As(err, &appErr) || appErr.Code != domain.ErrCodeQuotaExceeded {
+		abortError(c, http.StatusServiceUnavailable, domain.ErrCodeUnavailable, "quota store unavailable")
+		return
+	}
+
+	c.Header("Retry-After", strconv.Itoa(seconds(time.Until(usage.ResetAt))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{
+		Error:   appErr.Message,
+		Code:    appErr.Code,
+		Details: gin.H{"quota": usage},
+	})
+}
+
+func quotaSubject(c *gin.Context) quota.Subject {
+	identity, _ := identityFrom(c)
+	return quota.Subject{
+		UserID:   identity.UserID,
+		TenantID: identity.TenantID,
+		Plan:     identity.Plan,
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
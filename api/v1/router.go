@@ -0,0 +1,33 @@
+package v1
+
+// RegisterRoutes mounts every v1 endpoint under groups (normally /api/v1)
+func RegisterRoutes(groups Groups, h *ImageHandler) {
+	groups.Public.GET("/health", h.Health)
+	groups.Public.GET("/openapi.json", OpenAPI)
+
+	// Generation
+	groups.Generation.POST("/generate", h.Generate)
+	groups.Generation.POST("/generate/stream", h.GenerateStream)
+	groups.User.GET("/pipelines", h.ListPipelines)
+	groups.User.GET("/providers", h.ListProviders)
+
+	// Analysis and safety
+	groups.User.POST("/safety/check", h.SafetyCheck)
+	groups.User.POST("/expert/command", h.ExpertCommand)
+	groups.User.POST("/analyze/professional", h.AnalyzeProfessional)
+
+	// AI management
+	groups.Public.GET("/ai/status", h.GetAIStatus)
+	groups.Admin.GET("/ai/agents/:id", h.GetAgentDetails)
+	groups.Admin.GET("/ai/diagnostics", h.SystemDiagnostics)
+
+	// Moderation
+	groups.Admin.GET("/admin/moderation", h.ModerationViolations)
+	groups.Admin.POST("/admin/users/:user_id/shutdown", h.ShutdownUser)
+	groups.Admin.DELETE("/admin/users/:user_id/shutdown", h.RestoreUser)
+
+	// Demo
+	groups.Generation.GET("/demo/sample", h.GetSampleDemo)
+	groups.Public.GET("/demo/scenarios", h.ListDemoScenarios)
+	groups.Generation.POST("/demo/custom", h.ProcessCustomDemo)
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+)
+
+// streamMessage is what the generation goroutine hands to the SSE writer
+type streamMessage struct {
+	event string
+	data  interface{}
+}
+
+// GenerateStream is Generate as Server-Sent Events: one "progress" event
+// per finished stage, then a single "result" or "error" event.
+func (h *ImageHandler) GenerateStream(c *gin.Context) {
+	var request services.TierRequest
+	if !bindTierRequest(c, &request) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	messages := make(chan streamMessage, 16)
+
+	// send never blocks once the client is gone
+	send := func(message streamMessage) {
+		select {
+		case messages <- message:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(messages)
+
+		progressCtx := services.WithProgress(ctx, func(event domain.ProgressEvent) {
+			send(streamMessage{"progress", event})
+		})
+
+		result, err := h.graph.Generate(progressCtx, request)
+		if err != nil {
+			// The 200 is already sent, so tell the quota the generation failed
+			c.Set(generationFailedKey, true)
+			send(streamMessage{"error", serviceError(err)})
+			return
+		}
+		send(streamMessage{"result", result})
+	}()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	// c.Stream stops when the client disconnects; ctx is then cancelled and
+	// the generation goroutine stops sending
+	c.Stream(func(w io.Writer) bool {
+		message, ok := <-messages
+		if !ok {
+			return false
+		}
+		c.SSEvent(message.event, message.data)
+		return true
+	})
+}
@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/tenant"
+)
+
+// tenantSettings is everything an admin may change on a tenant
+type tenantSettings struct {
+	Name      string                        `json:"name" validate:"required,min=1,max=200"`
+	Plan      string                        `json:"plan,omitempty" validate:"enum=plan"`
+	Styles    map[string]domain.CustomStyle `json:"styles,omitempty"`
+	Policy    domain.SafetyPolicy           `json:"policy"`
+	Pipelines map[string][]string           `json:"pipelines,omitempty"`
+}
+
+type tenantRequest struct {
+	ID string `json:"id" validate:"required,min=1,max=63"`
+	tenantSettings
+}
+
+// tenantResponse is a tenant with its assigned users
+type tenantResponse struct {
+	*domain.Tenant
+	Members []string `json:"members"`
+}
+
+// Tenancy moves the authenticated caller into their workspace, so rate
+// limits, quotas and every service downstream see the resolved tenant
+func Tenancy(registry *tenant.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if identity, ok := identityFrom(c); ok {
+			setIdentity(c, registry.Resolve(identity))
+		}
+		c.Next()
+	}
+}
+
+// TenantHandler is the admin API for workspaces and their members
+type TenantHandler struct {
+	registry *tenant.Registry
+}
+
+func NewTenantHandler(registry *tenant.Registry) *TenantHandler {
+	return &TenantHandler{registry: registry}
+}
+
+func RegisterTenantRoutes(groups Groups, h *TenantHandler) {
+	groups.Admin.GET("/admin/tenants", h.ListTenants)
+	groups.Admin.POST("/admin/tenants", h.CreateTenant)
+	groups.Admin.GET("/admin/tenants/:id", h.GetTenant)
+	groups.Admin.PUT("/admin/tenants/:id", h.UpdateTenant)
+	groups.Admin.PUT("/admin/tenants/:id/users/:user_id", h.AssignUser)
+	groups.Admin.DELETE("/admin/tenants/:id/users/:user_id", h.UnassignUser)
+}
+
+func (h *TenantHandler) ListTenants(c *gin.Context) {
+	tenants := h.registry.List()
+	c.JSON(http.StatusOK, gin.H{
+		"tenants":   tenants,
+		"count":     len(tenants),
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+func (h *TenantHandler) CreateTenant(c *gin.Context) {
+	var request tenantRequest
+	if !bindJSON(c, &request) || !request.valid(c) {
+		return
+	}
+	if !tenant.ValidID(request.ID) {
+		respondFieldError(c, "id", "pattern", "must be lowercase letters, digits, - and _")
+		return
+	}
+
+	created, err := h.registry.Create(request.toTenant(request.ID))
+	switch {
+	case errors.Is(err, tenant.ErrExists):
+		respondError(c, http.StatusConflict, domain.ErrCodeConflict, err.Error())
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, domain.ErrCodeInternal, err.Error())
+		return
+	}
+
+	c.Header("Location", "/api/v1/admin/tenants/"+created.ID)
+	c.JSON(http.StatusCreated, tenantResponse{Tenant: created, Members: []string{}})
+}
+
+func (h *TenantHandler) GetTenant(c *gin.Context) {
+	found, ok := h.registry.Get(c.Param("id"))
+	if !ok {
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, tenant.ErrNotFound.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantResponse{Tenant: found, Members: h.registry.Members(found.ID)})
+}
+
+// UpdateTenant replaces the tenant's settings; omitted styles or policy
+// fields are cleared
+func (h *TenantHandler) UpdateTenant(c *gin.Context) {
+	var request tenantSettings
+	if !bindJSON(c, &request) || !request.valid(c) {
+		return
+	}
+
+	updated, err := h.registry.Update(request.toTenant(c.Param("id")))
+	if !h.ok(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, tenantResponse{Tenant: updated, Members: h.registry.Members(updated.ID)})
+}
+
+// AssignUser moves a user into the tenant, out of any other; it takes
+// effect on the user's next request
+func (h *TenantHandler) AssignUser(c *gin.Context) {
+	tenantID, userID := c.Param("id"), c.Param("user_id")
+	if !h.ok(c, h.registry.Assign(userID, tenantID)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tenant_id": tenantID, "user_id": userID})
+}
+
+func (h *TenantHandler) UnassignUser(c *gin.Context) {
+	if !h.ok(c, h.registry.Unassign(c.Param("user_id"), c.Param("id"))) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TenantHandler) ok(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, tenant.ErrNotFound), errors.Is(err, tenant.ErrNotMember):
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, err.Error())
+		return false
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, domain.ErrCodeInternal, err.Error())
+		return false
+	}
+	return true
+}
+
+func (s tenantSettings) toTenant(id string) domain.Tenant {
+	return domain.Tenant{
+		ID:        id,
+		Name:      s.Name,
+		Plan:      s.Plan,
+		Styles:    s.Styles,
+		Policy:    s.Policy,
+		Pipelines: s.Pipelines,
+	}
+}
+
+// valid checks the style names and the pipelines, which the schema cannot
+// express
+func (s tenantSettings) valid(c *gin.Context) bool {
+	for name := range s.Styles {
+		if strings.TrimSpace(name) == "" || len(name) > 64 {
+			respondFieldError(c, "styles", "key", "style names must be 1 to 64 characters")
+			return false
+		}
+	}
+
+	for tier, stages := range s.Pipelines {
+		if _, err := services.ParseTier(tier); err != nil {
+			respondFieldError(c, "pipelines", "key", err.Error())
+			return false
+		}
+		if err := services.ValidateStages(stages); err != nil {
+			respondFieldError(c, "pipelines."+tier, "stages", err.Error())
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/webhooks"
+)
+
+// WebhookHandler is the admin view of webhook delivery
+type WebhookHandler struct {
+	dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhookHandler(dispatcher *webhooks.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{dispatcher: dispatcher}
+}
+
+func RegisterWebhookRoutes(groups Groups, h *WebhookHandler) {
+	groups.Admin.GET("/admin/webhooks/dead-letters", h.ListDeadLetters)
+	groups.Admin.POST("/admin/webhooks/dead-letters/:id/retry", h.RetryDeadLetter)
+	groups.Admin.PUT("/admin/webhooks/tenants/:id", h.SetTenantEndpoint)
+	groups.Admin.DELETE("/admin/webhooks/tenants/:id", h.DeleteTenantEndpoint)
+}
+
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	letters := h.dispatcher.DeadLetters().List()
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"count":        len(letters),
+		"timestamp":    time.Now().UTC(),
+	})
+}
+
+func (h *WebhookHandler) RetryDeadLetter(c *gin.Context) {
+	err := h.dispatcher.Retry(c.Param("id"))
+	if errors.Is(err, webhooks.ErrDeadLetterNotFound) {
+		respondError(c, http.StatusNotFound, domain.ErrCodeNotFound, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"event_id": c.Param("id"), "status": "requeued"})
+}
+
+func (h *WebhookHandler) SetTenantEndpoint(c *gin.Context) {
+	var endpoint webhooks.Endpoint
+	if !bindJSON(c, &endpoint) {
+		return
+	}
+	if err := webhooks.ValidateURL(endpoint.URL); err != nil {
+		respondFieldError(c, "url", "invalid_url", err.Error())
+		return
+	}
+
+	h.dispatcher.Endpoints().Set(c.Param("id"), endpoint)
+	c.JSON(http.StatusOK, gin.H{"tenant_id": c.Param("id"), "url": endpoint.URL})
+}
+
+func (h *WebhookHandler) DeleteTenantEndpoint(c *gin.Context) {
+	h.dispatcher.Endpoints().Delete(c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// validWebhookURL writes a 400 and returns false for a bad per-request URL
+func validWebhookURL(c *gin.Context, raw string) bool {
+	if raw == "" {
+		return true
+	}
+	if err := webhooks.ValidateURL(raw); err != nil {
+		respondFieldError(c, "webhook_url", "invalid_url", err.Error())
+		return false
+	}
+	return true
+}
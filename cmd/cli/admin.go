@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+)
+
+const adminUsage = "Usage: geminizer admin <agents|health|moderation|shutdown-user> [options]"
+
+func handleAdmin() {
+	if len(os.Args) < 3 {
+		fail(adminUsage)
+	}
+
+	subcommand := os.Args[2]
+	args := os.Args[3:]
+
+	switch subcommand {
+	case "agents":
+		adminAgents(args)
+	case "health":
+		adminHealth(args)
+	case "moderation":
+		adminModeration(args)
+	case "shutdown-user":
+		adminShutdownUser(args)
+	default:
+		fail("Unknown admin command: %s\n%s", subcommand, adminUsage)
+	}
+}
+
+// adminAgents lists a running server's agents, or shows one when an ID
+// is given
+func adminAgents(args []string) {
+	fs := flag.NewFlagSet("admin agents", flag.ExitOnError)
+	server := addServerFlags(fs)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() > 0 {
+		var response struct {
+			Agent *management.AgentDetails `json:"agent"`
+		}
+		if err := server.call(http.MethodGet, "/ai/agents/"+url.PathEscape(fs.Arg(0)), nil, &response); err != nil {
+			fail("admin agents: %v", err)
+		}
+		printJSON(response.Agent)
+		return
+	}
+
+	var status struct {
+		OverallHealth      float64                  `json:"overall_health"`
+		AveragePerformance float64                  `json:"average_performance"`
+		SystemLoad         float64                  `json:"system_load"`
+		Uptime             string                   `json:"uptime"`
+		TotalAgents        int                      `json:"total_agents"`
+		Agents             []management.AgentStatus `json:"agents"`
+	}
+	if err := server.call(http.MethodGet, "/ai/status", nil, &status); err != nil {
+		fail("admin agents: %v", err)
+	}
+	if *asJSON {
+		printJSON(status)
+		return
+	}
+
+	fmt.Printf("Agents: %d   Health: %.2f   Performance: %.2f   Load: %.2f   Uptime: %s\n\n",
+		status.TotalAgents, status.OverallHealth, status.AveragePerformance,
+		status.SystemLoad, status.Uptime)
+
+	fmt.Printf("%-20s  %-30s  %-10s  %-6s  %s\n", "ID", "NAME", "STATUS", "HEALTH", "PERF")
+	for _, agent := range status.Agents {
+		fmt.Printf("%-20s  %-30s  %-10s  %-6.2f  %.2f\n",
+			agent.ID, agent.Name, agent.Status, agent.HealthScore, agent.Performance)
+	}
+}
+
+// adminHealth shows a running server's health monitor
+func adminHealth(args []string) {
+	fs := flag.NewFlagSet("admin health", flag.ExitOnError)
+	server := addServerFlags(fs)
+	asJSON := fs.Bool("json", false, "print as JSON")
+	fs.Parse(args)
+
+	var response struct {
+		Diagnostics *management.SystemHealth `json:"diagnostics"`
+	}
+	if err := server.call(http.MethodGet, "/ai/diagnostics", nil, &response); err != nil {
+		fail("admin health: %v", err)
+	}
+	health := response.Diagnostics
+	if health == nil {
+		fail("admin health: the server sent no diagnostics")
+	}
+	if *asJSON {
+		printJSON(health)
+		return
+	}
+
+	fmt.Printf("Status: %s   Score: %.2f   Checked: %s\n\n",
+		health.Status, health.Score, health.Timestamp.Format(time.RFC3339))
+
+	names := make([]string, 0, len(health.Metrics))
+	for name := range health.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("  %-14s %.2f\n", name, health.Metrics[name])
+	}
+}
+
+// adminModeration lists a running server's moderation violations, with
+// counts by type for the same filters
+func adminModeration(args []string) {
+	fs := flag.NewFlagSet("admin moderation", flag.ExitOnError)
+	server := addServerFlags(fs)
+	violationType := fs.String("type", "", "only violations of this type")
+	tenant := fs.String("tenant", "", "only violations from this tenant workspace")
+	since := fs.Duration("since", 0, "only violations newer than this, e.g. 24h")
+	limit := fs.Int("limit", 50, "maximum number of violations, 0 for all")
+	asJSON := fs.Bool("json", false, "print as JSON")
+	fs.Parse(args)
+
+	query := url.Values{}
+	if *violationType != "" {
+		query.Set("type", *violationType)
+	}
+	if *tenant != "" {
+		query.Set("tenant", *tenant)
+	}
+	if *since > 0 {
+		query.Set("since", since.String())
+	}
+	query.Set("limit", strconv.Itoa(*limit))
+
+	var response struct {
+		Violations []ai.ModerationViolation `json:"violations"`
+		Counts     map[string]int           `json:"counts"`
+		Total      int                      `json:"total"`
+	}
+	if err := server.call(http.MethodGet, "/admin/moderation?"+query.Encode(), nil, &response); err != nil {
+		fail("admin moderation: %v", err)
+	}
+	if *asJSON {
+		printJSON(response.Violations)
+		return
+	}
+
+	types := make([]string, 0, len(response.Counts))
+	for violation := range response.Counts {
+		types = append(types, violation)
+	}
+	sort.Strings(types)
+
+	fmt.Printf("Violations by type (%d total):\n", response.Total)
+	for _, violation := range types {
+		fmt.Printf("  %-20s %d\n", violation, response.Counts[violation])
+	}
+	fmt.Println()
+
+	for _, violation := range response.Violations {
+		fmt.Printf("%s  %-18s  %-12s  %s\n",
+			violation.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			violation.Type, violation.UserID, truncate(violation.Content, 80))
+	}
+}
+
+// adminShutdownUser blocks a user from generating on a running server,
+// or lets them back in with --lift. The server records either in its
+// moderation log.
+func adminShutdownUser(args []string) {
+	fs := flag.NewFlagSet("admin shutdown-user", flag.ExitOnError)
+	server := addServerFlags(fs)
+	user := fs.String("user", "", "user ID to shut down (required)")
+	reason := fs.String("reason", "", "reason recorded with the incident (required)")
+	lift := fs.Bool("lift", false, "lift the user's shutdown instead")
+	fs.Parse(args)
+
+	if *user == "" || *reason == "" {
+		fail("Usage: geminizer admin shutdown-user --user <id> --reason <text> [--lift]")
+	}
+
+	method := http.MethodPost
+	if *lift {
+		method = http.MethodDelete
+	}
+	path := "/admin/users/" + url.PathEscape(*user) + "/shutdown"
+	if err := server.call(method, path, map[string]string{"reason": *reason}, nil); err != nil {
+		fail("admin shutdown-user: %v", err)
+	}
+
+	if *lift {
+		fmt.Printf("Shutdown lifted for %s\n", *user)
+		return
+	}
+	fmt.Printf("Emergency shutdown triggered for %s\n", *user)
+}
+
+// serverFlags point an admin command at a running server's API
+type serverFlags struct {
+	url   *string
+	token *string
+}
+
+func addServerFlags(fs *flag.FlagSet) serverFlags {
+	server := os.Getenv("GEMINIZER_SERVER")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	return serverFlags{
+		url:   fs.String("server", server, "server base URL (GEMINIZER_SERVER)"),
+		token: fs.String("token", os.Getenv("GEMINIZER_TOKEN"), "admin bearer token (GEMINIZER_TOKEN)"),
+	}
+}
+
+// call sends body to an /api/v1 path and decodes the response into out
+func (s serverFlags) call(method string, path string, body interface{}, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(*s.url, "/")+"/api/v1"+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if *s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+*s.token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+		return fmt.Errorf("server: %s", apiErr.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-3]) + "..."
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+)
+
+// batchLine is one input line. Any TierRequest field may be set per line;
+// unset tier falls back to --tier.
+type batchLine struct {
+	ID string `json:"id"`
+	services.TierRequest
+}
+
+func handleBatch() {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	in := fs.String("in", "", "input JSONL file of prompts (required)")
+	out := fs.String("out", "", "output JSONL file of results (required)")
+	checkpoint := fs.String("checkpoint", "", "completed-ID checkpoint file (default <out>.checkpoint)")
+	concurrency := fs.Int("concurrency", 4, "number of generations run in parallel")
+	tierName := fs.String("tier", string(services.TierEnhanced), "default tier for lines without one")
+	user := fs.String("user", currentUser(), "default user ID for lines without one")
+	fs.Parse(os.Args[2:])
+
+	if *in == "" || *out == "" {
+		fail("Usage: geminizer batch --in prompts.jsonl --out results.jsonl [--concurrency N] [--tier master]")
+	}
+	if *checkpoint == "" {
+		*checkpoint = *out + ".checkpoint"
+	}
+
+	defaultTier, err := services.ParseTier(*tierName)
+	if err != nil {
+		fail("batch: %v", err)
+	}
+
+	done, err := loadCheckpoint(*checkpoint)
+	if err != nil {
+		fail("batch: %v", err)
+	}
+
+	items, invalid, err := readBatchInput(*in, defaultTier, *user, done)
+	if err != nil {
+		fail("batch: %v", err)
+	}
+
+	results, err := os.OpenFile(*out, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		fail("batch: %v", err)
+	}
+	defer results.Close()
+
+	progress, err := os.OpenFile(*checkpoint, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		fail("batch: %v", err)
+	}
+	defer progress.Close()
+
+	encoder := json.NewEncoder(results)
+	failed, retry := 0, 0
+	record := func(result services.BatchResult) {
+		if result.Error != "" {
+			failed++
+		}
+		// Result first, then checkpoint: a crash between the two repeats
+		// the line on resume rather than losing it
+		if err := encoder.Encode(result); err != nil {
+			fail("batch: write result: %v", err)
+		}
+		// A transient failure stays out of the checkpoint, so resuming
+		// tries the line again
+		if retryOnResume(result) {
+			retry++
+			return
+		}
+		fmt.Fprintln(progress, result.ID)
+	}
+
+	for _, result := range invalid {
+		record(result)
+	}
+
+	fmt.Fprintf(os.Stderr, "Running %d prompts (%d already done) with concurrency %d\n",
+		len(items), len(done), *concurrency)
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	completed := 0
+	newServiceGraph().GenerateBatch(ctx, items, *concurrency, func(result services.BatchResult) {
+		// A line cut short by the interrupt has no outcome; it goes in
+		// neither file, so resuming runs it again
+		if result.Error != "" && ctx.Err() != nil {
+			return
+		}
+		record(result)
+		completed++
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s %s\n", completed, len(items), result.ID, batchStatus(result))
+	})
+
+	if ctx.Err() != nil {
+		fail("batch interrupted after %d of %d prompts; rerun the same command to resume", completed, len(items))
+	}
+
+	fmt.Fprintf(os.Stderr, "Done: %d succeeded, %d failed\n", completed+len(invalid)-failed, failed)
+	if retry > 0 {
+		fmt.Fprintf(os.Stderr, "%d failures were transient; rerun the same command to retry them\n", retry)
+	}
+}
+
+// retryOnResume reports whether a failed line should run again when the
+// batch is resumed: the backend was unavailable or the user over a limit,
+// so a later run may succeed
+func retryOnResume(result services.BatchResult) bool {
+	switch result.Code {
+	case domain.ErrCodeUnavailable, domain.ErrCodeRateLimited, domain.ErrCodeQuotaExceeded:
+		return true
+	}
+	return false
+}
+
+func batchStatus(result services.BatchResult) string {
+	if result.Error != "" {
+		return "FAILED: " + result.Error
+	}
+	return fmt.Sprintf("ok (%dms)", result.DurationMs)
+}
+
+func loadCheckpoint(path string) (map[string]bool, error) {
+	done := make(map[string]bool)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %v", err)
+	}
+
+	for _, id := range strings.Split(string(data), "\n") {
+		if id = strings.TrimSpace(id); id != "" {
+			done[id] = true
+		}
+	}
+	return done, nil
+}
+
+// readBatchInput parses every line not yet checkpointed. Lines that cannot
+// be parsed become error results instead of aborting the batch.
+func readBatchInput(path string, defaultTier services.Tier, defaultUser string, done map[string]bool) ([]services.BatchItem, []services.BatchResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var items []services.BatchItem
+	var invalid []services.BatchResult
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var line batchLine
+		err := json.Unmarshal([]byte(text), &line)
+		if line.ID == "" {
+			line.ID = fmt.Sprintf("line-%d", lineNumber)
+		}
+		if done[line.ID] {
+			continue
+		}
+		if seen[line.ID] {
+			err = fmt.Errorf("duplicate id %q", line.ID)
+		}
+		seen[line.ID] = true
+
+		if err == nil {
+			err = completeBatchRequest(&line.TierRequest, defaultTier, defaultUser)
+		}
+		if err != nil {
+			invalid = append(invalid, services.BatchResult{ID: line.ID, Error: err.Error()})
+			continue
+		}
+
+		items = append(items, services.BatchItem{ID: line.ID, Request: line.TierRequest})
+	}
+
+	return items, invalid, scanner.Err()
+}
+
+func completeBatchRequest(req *services.TierRequest, defaultTier services.Tier, defaultUser string) error {
+	if strings.TrimSpace(req.Prompt) == "" {
+		return fmt.Errorf("missing prompt")
+	}
+
+	if req.Tier == "" {
+		req.Tier = defaultTier
+	} else if _, err := services.ParseTier(string(req.Tier)); err != nil {
+		return err
+	}
+
+	if req.UserID == "" {
+		req.UserID = defaultUser
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/textdiff"
+)
+
+// handleExplain runs a generation with tracing on and shows how each
+// stage and agent rewrote the prompt
+func handleExplain() {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	prompt := fs.String("prompt", "", "prompt text")
+	file := fs.String("file", "", "read the prompt from a file")
+	tierName := fs.String("tier", string(services.TierMaster), "pipeline tier: enhanced, final, enterprise, 3d, master")
+	style := fs.String("style", "", "art style")
+	filter := fs.String("filter", "", "camera filter")
+	shotType := fs.String("shot-type", "", "studio shot type")
+	mood := fs.String("mood", "", "studio mood")
+	all := fs.Bool("all", false, "also show steps that left the prompt unchanged")
+	noColor := fs.Bool("no-color", false, "mark changes with [-removed-] and {+added+} instead of colors")
+	asJSON := fs.Bool("json", false, "print the result and trace as JSON")
+	fs.Parse(os.Args[2:])
+
+	text, err := readPrompt(*prompt, *file)
+	if err != nil {
+		fail("explain: %v", err)
+	}
+
+	tier, err := services.ParseTier(*tierName)
+	if err != nil {
+		fail("explain: %v", err)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	// Attach the trace up front so a failed run still shows how far it got
+	trace := domain.NewPipelineTrace()
+	result, err := newServiceGraph().Generate(services.WithTrace(ctx, trace), services.TierRequest{
+		Tier:     tier,
+		Prompt:   text,
+		Style:    *style,
+		Filter:   *filter,
+		ShotType: *shotType,
+		Mood:     *mood,
+		UserID:   currentUser(),
+		Explain:  true,
+	})
+
+	if *asJSON {
+		if err != nil {
+			printJSON(map[string]interface{}{"error": err.Error(), "trace": trace})
+			os.Exit(1)
+		}
+		printJSON(result)
+		return
+	}
+
+	color := !*noColor && os.Getenv("NO_COLOR") == ""
+	printTrace(trace, *all, color)
+
+	if err != nil {
+		fail("\nexplain: generation failed after the steps above: %v", err)
+	}
+
+	fmt.Println()
+	printTierResult(result)
+}
+
+func printTrace(trace *domain.PipelineTrace, all bool, color bool) {
+	shown := 0
+	for i, step := range trace.Steps {
+		if !step.Changed && !all {
+			continue
+		}
+		shown++
+
+		fmt.Printf("%2d. %-10s %s\n", i+1, step.Stage, step.Agent)
+		if !step.Changed {
+			fmt.Println("    (unchanged)")
+			continue
+		}
+		fmt.Printf("    %s\n\n", textdiff.Render(textdiff.Words(step.Before, step.After), color))
+	}
+
+	fmt.Printf("%d of %d steps changed the prompt\n", countChanged(trace), len(trace.Steps))
+	if shown == 0 && len(trace.Steps) > 0 {
+		fmt.Println("Use --all to list unchanged steps")
+	}
+}
+
+func countChanged(trace *domain.PipelineTrace) int {
+	changed := 0
+	for _, step := range trace.Steps {
+		if step.Changed {
+			changed++
+		}
+	}
+	return changed
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+func handleGenerate() {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	prompt := fs.String("prompt", "", "prompt text")
+	file := fs.String("file", "", "read the prompt from a file")
+	tierName := fs.String("tier", string(services.TierEnhanced), "pipeline tier: enhanced, final, enterprise, 3d, master")
+	style := fs.String("style", "", "art style, e.g. unreal_engine_5, manwha, glamour_photography")
+	filter := fs.String("filter", "", "camera filter, e.g. cinematic, vintage_camera, old_polaroid")
+	shotType := fs.String("shot-type", "", "studio shot type (3d and master tiers)")
+	mood := fs.String("mood", "", "studio mood (3d and master tiers)")
+	profile := fs.String("profile", "", "named pipeline profile from the pipeline config")
+	provider := fs.String("provider", "", "image provider: gemini, automatic1111, openai or auto (default IMAGE_PROVIDER)")
+	negative := fs.String("negative-prompt", "", "what the image must not show (providers that support it)")
+	aspectRatio := fs.String("aspect-ratio", "", "image aspect ratio, e.g. 1:1, 16:9, 9:16")
+	dryRun := fs.Bool("dry-run", false, "review and check the prompt but draw no image")
+	user := fs.String("user", currentUser(), "user ID recorded with the generation")
+	asJSON := fs.Bool("json", false, "print the full result as JSON")
+	fs.Parse(os.Args[2:])
+
+	text, err := readPrompt(*prompt, *file)
+	if err != nil {
+		fail("generate: %v", err)
+	}
+
+	tier, err := services.ParseTier(*tierName)
+	if err != nil {
+		fail("generate: %v", err)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	result, err := newServiceGraph().Generate(ctx, services.TierRequest{
+		Tier:     tier,
+		Prompt:   text,
+		Style:    *style,
+		Filter:   *filter,
+		ShotType: *shotType,
+		Mood:     *mood,
+		Profile:  *profile,
+		UserID:   *user,
+
+		Provider:       *provider,
+		NegativePrompt: *negative,
+		AspectRatio:    *aspectRatio,
+		DryRun:         *dryRun,
+	})
+	if err != nil {
+		fail("generate: %v", err)
+	}
+
+	if *asJSON {
+		printJSON(result)
+		return
+	}
+	printTierResult(result)
+}
+
+// readPrompt takes the prompt from --prompt or --file, never both
+func readPrompt(prompt string, file string) (string, error) {
+	if prompt != "" && file != "" {
+		return "", fmt.Errorf("use either --prompt or --file, not both")
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("read prompt file: %v", err)
+		}
+		prompt = string(data)
+	}
+
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return "", fmt.Errorf("a prompt is required (--prompt or --file)")
+	}
+	return prompt, nil
+}
+
+func printTierResult(result *services.TierResult) {
+	fmt.Printf("Tier:         %s\n", result.Tier)
+	if result.DryRun {
+		fmt.Println("Image:        none (dry run)")
+	} else {
+		fmt.Printf("Image:        %s\n", result.ImageURL)
+	}
+	fmt.Printf("Final prompt:\n  %s\n", result.FinalPrompt)
+
+	if analysis := result.Analysis; analysis != nil {
+		fmt.Println("Analysis:")
+		fmt.Printf("  Intent:             %s\n", analysis.Intent)
+		fmt.Printf("  Quality score:      %.2f\n", analysis.QualityScore)
+		fmt.Printf("  Professional level: %s\n", analysis.ProfessionalLevel)
+		for _, strength := range analysis.Strengths {
+			fmt.Printf("  + %s\n", strength)
+		}
+		for _, area := range analysis.ImprovementAreas {
+			fmt.Printf("  - %s\n", area)
+		}
+	}
+}
+
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fail("encode json: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/repository/local"
+)
+
+const historyUsage = "Usage: geminizer history <list|show|search|export|delete> [options]"
+
+func handleHistory() {
+	if len(os.Args) < 3 {
+		fail(historyUsage)
+	}
+
+	subcommand := os.Args[2]
+	args := os.Args[3:]
+
+	switch subcommand {
+	case "list":
+		historyList(args)
+	case "show":
+		historyShow(args)
+	case "search":
+		historySearch(args)
+	case "export":
+		historyExport(args)
+	case "delete":
+		historyDelete(args)
+	default:
+		fail("Unknown history command: %s\n%s", subcommand, historyUsage)
+	}
+}
+
+// historyFlags registers the filter flags shared by list, search and export
+type historyFlags struct {
+	db         *string
+	user       *string
+	tenant     *string
+	from       *string
+	to         *string
+	intent     *string
+	style      *string
+	minQuality *float64
+	maxQuality *float64
+	limit      *int
+}
+
+func newHistoryFlags(fs *flag.FlagSet, defaultLimit int) *historyFlags {
+	return &historyFlags{
+		db:         fs.String("db", defaultHistoryPath(), "history store path"),
+		user:       fs.String("user", "", "only records for this user"),
+		tenant:     fs.String("tenant", "", "only records from this tenant workspace"),
+		from:       fs.String("from", "", "only records on or after this date (YYYY-MM-DD or RFC3339)"),
+		to:         fs.String("to", "", "only records on or before this date (YYYY-MM-DD or RFC3339)"),
+		intent:     fs.String("intent", "", "only records with this intent (portrait, fashion, ...)"),
+		style:      fs.String("style", "", "only records with this art style"),
+		minQuality: fs.Float64("min-quality", 0, "minimum quality score (0-1)"),
+		maxQuality: fs.Float64("max-quality", 0, "maximum quality score (0-1)"),
+		limit:      fs.Int("limit", defaultLimit, "maximum number of records, 0 for all"),
+	}
+}
+
+func (h *historyFlags) filter() domain.HistoryFilter {
+	filter := domain.HistoryFilter{
+		UserID:     *h.user,
+		TenantID:   *h.tenant,
+		Intent:     *h.intent,
+		Style:      *h.style,
+		MinQuality: *h.minQuality,
+		MaxQuality: *h.maxQuality,
+		Limit:      *h.limit,
+	}
+
+	var err error
+	if *h.from != "" {
+		if filter.From, err = parseDate(*h.from, false); err != nil {
+			fail("history: --from: %v", err)
+		}
+	}
+	if *h.to != "" {
+		if filter.To, err = parseDate(*h.to, true); err != nil {
+			fail("history: --to: %v", err)
+		}
+	}
+
+	return filter
+}
+
+// parseDate accepts a plain date or a full timestamp. A plain --to date
+// covers the whole day.
+func parseDate(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+func historyList(args []string) {
+	fs := flag.NewFlagSet("history list", flag.ExitOnError)
+	flags := newHistoryFlags(fs, 20)
+	fs.Parse(args)
+
+	printHistory(searchHistory(flags))
+}
+
+func historySearch(args []string) {
+	fs := flag.NewFlagSet("history search", flag.ExitOnError)
+	flags := newHistoryFlags(fs, 0)
+	asJSON := fs.Bool("json", false, "print matching records as JSON")
+	fs.Parse(args)
+
+	records := searchHistory(flags)
+	if *asJSON {
+		printJSON(records)
+		return
+	}
+	printHistory(records)
+}
+
+func historyExport(args []string) {
+	fs := flag.NewFlagSet("history export", flag.ExitOnError)
+	flags := newHistoryFlags(fs, 0)
+	format := fs.String("format", "jsonl", "export format: jsonl or csv")
+	out := fs.String("out", "", "output file (default stdout)")
+	fs.Parse(args)
+
+	records := searchHistory(flags)
+
+	writer := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fail("history export: %v", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if err := local.Export(writer, *format, records); err != nil {
+		fail("history export: %v", err)
+	}
+
+	if *out != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d records to %s\n", len(records), *out)
+	}
+}
+
+func historyShow(args []string) {
+	fs := flag.NewFlagSet("history show", flag.ExitOnError)
+	db := fs.String("db", defaultHistoryPath(), "history store path")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fail("Usage: geminizer history show [--db path] <id>")
+	}
+
+	record, err := openHistoryStore(*db).Get(context.Background(), fs.Arg(0))
+	if err != nil {
+		fail("history show: %v", err)
+	}
+	printJSON(record)
+}
+
+func historyDelete(args []string) {
+	fs := flag.NewFlagSet("history delete", flag.ExitOnError)
+	db := fs.String("db", defaultHistoryPath(), "history store path")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fail("Usage: geminizer history delete [--db path] <id>...")
+	}
+
+	store := openHistoryStore(*db)
+	for _, id := range fs.Args() {
+		if err := store.Delete(context.Background(), id); err != nil {
+			fail("history delete: %v", err)
+		}
+		fmt.Printf("Deleted %s\n", id)
+	}
+}
+
+func searchHistory(flags *historyFlags) []*domain.GenerationRecord {
+	records, err := openHistoryStore(*flags.db).Search(context.Background(), flags.filter())
+	if err != nil {
+		fail("history: %v", err)
+	}
+	return records
+}
+
+func printHistory(records []*domain.GenerationRecord) {
+	if len(records) == 0 {
+		fmt.Println("No history records found")
+		return
+	}
+
+	fmt.Printf("%-16s  %-20s  %-12s  %-10s  %-12s  %-20s  %s\n",
+		"ID", "CREATED", "USER", "TIER", "INTENT", "STYLE", "QUALITY")
+	for _, record := range records {
+		fmt.Printf("%-16s  %-20s  %-12s  %-10s  %-12s  %-20s  %.2f\n",
+			record.ID,
+			record.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			record.UserID,
+			record.Tier,
+			record.Intent,
+			record.Style,
+			record.QualityScore)
+	}
+}
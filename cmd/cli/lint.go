@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/logging"
+	"geminizer-enterprise/internal/repository/memory"
+)
+
+// Exit codes for CI: findings at or above --fail-on exit 1, usage and
+// I/O problems exit 2
+const (
+	lintExitFindings = 1
+	lintExitError    = 2
+)
+
+type lintReport struct {
+	File     string                 `json:"file"`
+	Findings []services.LintFinding `json:"findings"`
+}
+
+func handleLint() {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, json or sarif")
+	failOn := fs.String("fail-on", "error", "lowest severity that fails the run: info, warning, error or none")
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() == 0 {
+		lintFail("Usage: geminizer lint [--format text|json|sarif] [--fail-on error] <files>...")
+	}
+
+	threshold, err := lintThreshold(*failOn)
+	if err != nil {
+		lintFail("lint: %v", err)
+	}
+
+	// Validation only needs the master agent; history is never written
+	logger := logging.NewStdLogger(os.Stderr)
+	master := services.NewMasterGenerationService(memory.NewHistoryRepository(), logger)
+	linter := services.NewPromptLinter(master)
+
+	var reports []lintReport
+	for _, path := range expandLintArgs(fs.Args()) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			lintFail("lint: %v", err)
+		}
+
+		prompt := strings.TrimSpace(string(data))
+		findings := []services.LintFinding{}
+		if prompt == "" {
+			findings = append(findings, services.LintFinding{Rule: "prompt/empty", Severity: services.SeverityError, Message: "Prompt file is empty"})
+		} else {
+			findings = linter.Lint(prompt)
+		}
+
+		reports = append(reports, lintReport{File: path, Findings: findings})
+	}
+
+	switch *format {
+	case "text":
+		printLintText(reports)
+	case "json":
+		printJSON(reports)
+	case "sarif":
+		printJSON(newSarifLog(reports))
+	default:
+		lintFail("lint: unknown format %q", *format)
+	}
+
+	if threshold != nil && lintFailed(reports, *threshold) {
+		os.Exit(lintExitFindings)
+	}
+}
+
+func lintFail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(lintExitError)
+}
+
+// lintThreshold returns nil for "none", which never fails the run
+func lintThreshold(name string) (*services.Severity, error) {
+	if name == "none" {
+		return nil, nil
+	}
+	severity, err := services.ParseSeverity(name)
+	if err != nil {
+		return nil, err
+	}
+	return &severity, nil
+}
+
+// expandLintArgs expands globs for shells that pass them through unexpanded
+func expandLintArgs(args []string) []string {
+	var paths []string
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil || len(matches) == 0 {
+			paths = append(paths, arg)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+func lintFailed(reports []lintReport, threshold services.Severity) bool {
+	for _, report := range reports {
+		for _, finding := range report.Findings {
+			if finding.Severity >= threshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func printLintText(reports []lintReport) {
+	counts := make(map[services.Severity]int)
+	for _, report := range reports {
+		for _, finding := range report.Findings {
+			counts[finding.Severity]++
+			fmt.Printf("%s: %s: %s [%s]\n", report.File, finding.Severity, finding.Message, finding.Rule)
+		}
+	}
+
+	fmt.Printf("\n%d files: %d errors, %d warnings, %d info\n", len(reports),
+		counts[services.SeverityError], counts[services.SeverityWarning], counts[services.SeverityInfo])
+}
@@ -11,7 +11,8 @@ import (
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: geminizer <command> [options]")
-		fmt.Println("Commands: generate, history, admin, version")
+		fmt.Println("Commands: generate, batch, explain, lint, shell, serve, history, admin, version")
+		fmt.Println("Run 'geminizer <command> -h' for command options")
 		os.Exit(1)
 	}
 	
@@ -20,6 +21,16 @@ func main() {
 	switch command {
 	case "generate":
 		handleGenerate()
+	case "batch":
+		handleBatch()
+	case "explain":
+		handleExplain()
+	case "lint":
+		handleLint()
+	case "shell":
+		handleShell()
+	case "serve":
+		handleServe()
 	case "history":
 		handleHistory()
 	case "admin":
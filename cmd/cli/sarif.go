@@ -0,0 +1,110 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+// Minimal SARIF 2.1.0 structures, enough for GitHub code scanning
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func newSarifLog(reports []lintReport) *sarifLog {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "geminizer-lint"}},
+		Results: []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+	for _, report := range reports {
+		for _, finding := range report.Findings {
+			rules[finding.Rule] = true
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  finding.Rule,
+				Level:   sarifLevel(finding.Severity),
+				Message: sarifMessage{Text: finding.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(report.File)},
+						Region:           sarifRegion{StartLine: 1},
+					},
+				}},
+			})
+		}
+	}
+
+	for rule := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
+func sarifLevel(severity services.Severity) string {
+	switch severity {
+	case services.SeverityError:
+		return "error"
+	case services.SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"geminizer-enterprise/internal/logging"
+	"geminizer-enterprise/internal/server"
+)
+
+// handleServe runs the HTTP API; flags override the environment
+func handleServe() {
+	config, err := server.LoadConfig()
+	if err != nil {
+		fail("serve: %v", err)
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&config.Port, "port", config.Port, "listen port (PORT)")
+	fs.StringVar(&config.Env, "env", config.Env, "environment name (ENV)")
+	fs.StringVar(&config.HistoryPath, "history", config.HistoryPath, "local history store path (HISTORY_PATH), memory if empty")
+	fs.StringVar(&config.ModerationLog, "moderation-log", config.ModerationLog, "moderation log path (MODERATION_LOG)")
+	fs.Parse(os.Args[2:])
+
+	logger := logging.NewStdLogger(os.Stdout)
+
+	srv, err := server.New(config, logger)
+	if err != nil {
+		fail("serve: %v", err)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	if err := srv.Run(ctx); err != nil {
+		fail("serve: %v", err)
+	}
+}
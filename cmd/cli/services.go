@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/imaging"
+	"geminizer-enterprise/internal/logging"
+	"geminizer-enterprise/internal/profiles"
+	"geminizer-enterprise/internal/repository/local"
+)
+
+// newServiceGraph builds the generation services for a CLI invocation.
+// Logs go to stderr so stdout stays clean for --json output.
+func newServiceGraph() *services.ServiceGraph {
+	logger := logging.NewStdLogger(os.Stderr)
+	graph := services.NewServiceGraph(openHistoryStore(defaultHistoryPath()), logger)
+	if err := loadPipelineConfig().Apply(graph); err != nil {
+		fail("%v", err)
+	}
+	routing := services.DefaultRoutingConfig()
+	if err := graph.SetImageProviders(imageProviders(routing.Timeout), os.Getenv("IMAGE_PROVIDER"), routing); err != nil {
+		fail("%v", err)
+	}
+	return graph
+}
+
+// imageProviders registers the backends configured in the environment,
+// with the variables the server reads
+func imageProviders(timeout time.Duration) []services.ImageProvider {
+	return imaging.Providers(imaging.Config{
+		Timeout: timeout,
+		Gemini: imaging.GeminiConfig{
+			BaseURL: os.Getenv("GEMINI_BASE_URL"),
+			APIKey:  os.Getenv("GEMINI_API_KEY"),
+			Model:   os.Getenv("GEMINI_IMAGE_MODEL"),
+		},
+		Automatic1111: imaging.Automatic1111Config{
+			BaseURL: os.Getenv("A1111_BASE_URL"),
+		},
+		OpenAI: imaging.OpenAIConfig{
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   os.Getenv("OPENAI_IMAGE_MODEL"),
+		},
+	})
+}
+
+// loadPipelineConfig reads PIPELINE_CONFIG, as the server does, falling
+// back to the builtin profiles
+func loadPipelineConfig() *profiles.Config {
+	var (
+		config *profiles.Config
+		err    error
+	)
+	if path := os.Getenv("PIPELINE_CONFIG"); path != "" {
+		config, err = profiles.Load(path)
+	} else {
+		config, err = profiles.Builtin()
+	}
+	if err != nil {
+		fail("%v", err)
+	}
+	return config
+}
+
+// geminizerHome is where the CLI keeps local state (history, checkpoints)
+func geminizerHome() string {
+	if home := os.Getenv("GEMINIZER_HOME"); home != "" {
+		return home
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".geminizer")
+	}
+	return ".geminizer"
+}
+
+func defaultHistoryPath() string {
+	return filepath.Join(geminizerHome(), "history.jsonl")
+}
+
+func openHistoryStore(path string) *local.HistoryStore {
+	store, err := local.OpenHistoryStore(path)
+	if err != nil {
+		fail("history: %v", err)
+	}
+	return store
+}
+
+// signalContext is cancelled on Ctrl-C so long generations stop cleanly
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+func currentUser() string {
+	if user := os.Getenv("GEMINIZER_USER"); user != "" {
+		return user
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "cli-user"
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/services"
+)
+
+const shellHelp = `Type a directive to refine the prompt, or a number to apply a suggestion.
+
+  :show prompt          print the working prompt
+  :show state           print the conversation state
+  :set prompt <text>    replace the working prompt
+  :undo                 revert the last change to the prompt
+  :generate [tier]      generate an image from the working prompt
+  :expertise <level>    beginner, intermediate or expert
+  :save [name]          save the session
+  :load <name>          load a saved session
+  :help                 show this help
+  :quit                 leave the shell`
+
+// shellSession is everything the shell keeps between turns and on disk
+type shellSession struct {
+	Name        string     `json:"name"`
+	Prompt      string     `json:"prompt"`
+	Undo        []string   `json:"undo"`
+	Tier        string     `json:"tier"`
+	State       ai.UIState `json:"state"`
+	Suggestions []string   `json:"suggestions"`
+}
+
+type shell struct {
+	session *shellSession
+	ui      *ai.ConsciousUI
+	graph   *services.ServiceGraph
+}
+
+func handleShell() {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	session := fs.String("session", "default", "session name to resume or create")
+	tier := fs.String("tier", string(services.TierMaster), "tier used by :generate")
+	expertise := fs.String("expertise", "expert", "beginner, intermediate or expert")
+	fs.Parse(os.Args[2:])
+
+	sh := &shell{
+		ui: ai.NewConsciousUI(),
+		session: &shellSession{
+			Name: *session,
+			Tier: *tier,
+			State: ai.UIState{
+				UserExpertise:   *expertise,
+				CurrentTask:     "prompt_refinement",
+				UserPreferences: make(map[string]interface{}),
+			},
+		},
+	}
+
+	if loaded, err := loadShellSession(*session); err == nil {
+		sh.session = loaded
+		fmt.Printf("Resumed session %q\n", loaded.Name)
+	}
+
+	fmt.Println("Geminizer expert shell. Type :help for commands.")
+	sh.run()
+}
+
+func (s *shell) run() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Printf("%s> ", s.session.Name)
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if !s.runCommand(line) {
+				return
+			}
+			continue
+		}
+
+		if choice, err := strconv.Atoi(line); err == nil {
+			s.applySuggestion(choice)
+			continue
+		}
+
+		s.refine(line)
+	}
+}
+
+// runCommand handles a :command and returns false when the shell should exit
+func (s *shell) runCommand(line string) bool {
+	fields := strings.Fields(line)
+	arg := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+
+	switch fields[0] {
+	case ":quit", ":q", ":exit":
+		s.save(s.session.Name)
+		return false
+	case ":help":
+		fmt.Println(shellHelp)
+	case ":show":
+		s.show(arg)
+	case ":set":
+		if !strings.HasPrefix(arg, "prompt ") {
+			fmt.Println("Usage: :set prompt <text>")
+			break
+		}
+		s.setPrompt(strings.TrimSpace(strings.TrimPrefix(arg, "prompt ")))
+	case ":undo":
+		s.undo()
+	case ":generate":
+		s.generate(arg)
+	case ":expertise":
+		s.session.State.UserExpertise = arg
+		fmt.Printf("Expertise set to %s\n", arg)
+	case ":save":
+		if arg == "" {
+			arg = s.session.Name
+		}
+		s.save(arg)
+	case ":load":
+		loaded, err := loadShellSession(arg)
+		if err != nil {
+			fmt.Printf("Cannot load %q: %v\n", arg, err)
+			break
+		}
+		s.session = loaded
+		fmt.Printf("Loaded session %q\n", arg)
+	default:
+		fmt.Printf("Unknown command %s (try :help)\n", fields[0])
+	}
+	return true
+}
+
+// refine sends a directive through ConsciousUI and folds it into the prompt
+func (s *shell) refine(directive string) {
+	response := s.ui.ProcessUserCommandWithState(directive, &s.session.State)
+	s.session.State.PreviousActions = append(s.session.State.PreviousActions, directive)
+
+	if s.session.Prompt == "" {
+		s.setPrompt(directive)
+	} else {
+		s.setPrompt(s.session.Prompt + ", " + directive)
+	}
+
+	fmt.Printf("[%s %.0f%%] %s\n", response.DetectedIntent, response.Confidence*100, response.Message)
+
+	s.session.Suggestions = response.Suggestions
+	for i, suggestion := range s.session.Suggestions {
+		fmt.Printf("  %d) %s\n", i+1, suggestion)
+	}
+}
+
+func (s *shell) applySuggestion(choice int) {
+	if choice < 1 || choice > len(s.session.Suggestions) {
+		fmt.Printf("No suggestion %d\n", choice)
+		return
+	}
+
+	suggestion := s.session.Suggestions[choice-1]
+	s.session.State.PreviousActions = append(s.session.State.PreviousActions, "suggestion: "+suggestion)
+	s.setPrompt(s.session.Prompt + ", " + suggestion)
+	fmt.Printf("Applied: %s\n", suggestion)
+}
+
+func (s *shell) setPrompt(prompt string) {
+	s.session.Undo = append(s.session.Undo, s.session.Prompt)
+	s.session.Prompt = prompt
+}
+
+func (s *shell) undo() {
+	if len(s.session.Undo) == 0 {
+		fmt.Println("Nothing to undo")
+		return
+	}
+
+	last := len(s.session.Undo) - 1
+	s.session.Prompt = s.session.Undo[last]
+	s.session.Undo = s.session.Undo[:last]
+
+	if actions := s.session.State.PreviousActions; len(actions) > 0 {
+		s.session.State.PreviousActions = actions[:len(actions)-1]
+	}
+	s.session.Suggestions = nil
+
+	fmt.Printf("Prompt: %s\n", s.session.Prompt)
+}
+
+func (s *shell) show(what string) {
+	switch what {
+	case "prompt", "":
+		fmt.Printf("Prompt: %s\n", s.session.Prompt)
+	case "state":
+		data, _ := json.MarshalIndent(s.session.State, "", "  ")
+		fmt.Println(string(data))
+	default:
+		fmt.Println("Usage: :show prompt|state")
+	}
+}
+
+func (s *shell) generate(tierName string) {
+	if tierName == "" {
+		tierName = s.session.Tier
+	}
+	tier, err := services.ParseTier(tierName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if strings.TrimSpace(s.session.Prompt) == "" {
+		fmt.Println("The prompt is empty")
+		return
+	}
+
+	// Built lazily: most sessions refine for a while before generating
+	if s.graph == nil {
+		s.graph = newServiceGraph()
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	result, err := s.graph.Generate(ctx, services.TierRequest{
+		Tier:   tier,
+		Prompt: s.session.Prompt,
+		UserID: currentUser(),
+	})
+	if err != nil {
+		fmt.Printf("Generation failed: %v\n", err)
+		return
+	}
+
+	printTierResult(result)
+}
+
+func (s *shell) save(name string) {
+	s.session.Name = name
+	if err := saveShellSession(s.session); err != nil {
+		fmt.Printf("Cannot save session: %v\n", err)
+		return
+	}
+	fmt.Printf("Saved session %q\n", name)
+}
+
+func shellSessionPath(name string) string {
+	return filepath.Join(geminizerHome(), "sessions", filepath.Base(name)+".json")
+}
+
+func saveShellSession(session *shellSession) error {
+	path := shellSessionPath(session.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func loadShellSession(name string) (*shellSession, error) {
+	data, err := os.ReadFile(shellSessionPath(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var session shellSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	if session.State.UserPreferences == nil {
+		session.State.UserPreferences = make(map[string]interface{})
+	}
+	return &session, nil
+}
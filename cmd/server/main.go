@@ -0,0 +1,36 @@
+// cmd/server/main.go
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"geminizer-enterprise/internal/logging"
+	"geminizer-enterprise/internal/server"
+)
+
+func main() {
+	logger := logging.NewStdLogger(os.Stdout)
+
+	config, err := server.LoadConfig()
+	if err != nil {
+		logger.Error("invalid configuration", err)
+		os.Exit(1)
+	}
+
+	srv, err := server.New(config, logger)
+	if err != nil {
+		logger.Error("server setup failed", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("server stopped", err)
+		os.Exit(1)
+	}
+}
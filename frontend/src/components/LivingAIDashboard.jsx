@@ -7,30 +7,103 @@ const LivingAIDashboard = () => {
 
     useEffect(() => {
         fetchSystemStatus();
-        const interval = setInterval(fetchSystemStatus, 10000); // Update every 10 seconds
-        return () => clearInterval(interval);
+        let interval = null;
+
+        // Live feed; fall back to polling when it is unavailable
+        const token = localStorage.getItem('auth_token');
+        const protocol = window.location.protocol === 'https:' ? 'wss' : 'ws';
+        const params = new URLSearchParams({ topics: 'status' });
+        if (token) {
+            params.set('access_token', token);
+        }
+        const socket = new WebSocket(`${protocol}://${window.location.host}/api/v1/ws/feed?${params}`);
+
+        socket.onmessage = (message) => {
+            const event = JSON.parse(message.data);
+            if (event.type === 'status.snapshot') {
+                applyStatus(event.data);
+            } else if (event.type === 'status.delta') {
+                applyDelta(event.data);
+            } else if (event.type === 'dropped') {
+                socket.send(JSON.stringify({ action: 'resync' }));
+            }
+        };
+        socket.onclose = () => {
+            if (!interval) {
+                interval = setInterval(fetchSystemStatus, 10000); // Update every 10 seconds
+            }
+        };
+
+        return () => {
+            socket.onclose = null;
+            socket.close();
+            clearInterval(interval);
+        };
     }, []);
 
     const fetchSystemStatus = async () => {
         try {
             const response = await fetch('/api/v1/ai/status');
             const status = await response.json();
-            setSystemStatus(status);
-            updateAgentActivity(status.agents);
+            applyStatus(status);
         } catch (error) {
             console.error('Failed to fetch system status:', error);
         }
     };
 
+    const applyStatus = (status) => {
+        const agents = status.agents || [];
+        setSystemStatus({
+            overallHealth: status.overall_health,
+            averagePerformance: status.average_performance,
+            systemLoad: status.system_load,
+            totalAgents: status.total_agents,
+            agentsUsed: agents.length,
+            agents,
+        });
+        updateAgentActivity(agents);
+    };
+
+    // Deltas carry only what changed: summary fields, changed agents and removed agent IDs
+    const applyDelta = (delta) => {
+        setSystemStatus(previous => {
+            if (!previous) {
+                return previous;
+            }
+
+            const removed = new Set(delta.removed || []);
+            const changed = new Map((delta.agents || []).map(agent => [agent.id, agent]));
+            const agents = previous.agents
+                .filter(agent => !removed.has(agent.id))
+                .map(agent => changed.get(agent.id) || agent);
+            for (const agent of changed.values()) {
+                if (!agents.some(existing => existing.id === agent.id)) {
+                    agents.push(agent);
+                }
+            }
+
+            updateAgentActivity(agents);
+            return {
+                ...previous,
+                overallHealth: delta.overall_health ?? previous.overallHealth,
+                averagePerformance: delta.average_performance ?? previous.averagePerformance,
+                systemLoad: delta.system_load ?? previous.systemLoad,
+                totalAgents: delta.total_agents ?? previous.totalAgents,
+                agentsUsed: agents.length,
+                agents,
+            };
+        });
+    };
+
     const updateAgentActivity = (agents) => {
-        const activity = Object.entries(agents).map(([id, agent]) => ({
-            id,
+        const activity = agents.map(agent => ({
+            id: agent.id,
             name: agent.name,
             role: agent.role,
-            health: agent.health,
+            health: agent.health_score,
             performance: agent.performance,
-            lastActive: new Date(agent.lastActive),
-            isActive: agent.isActive
+            lastActive: new Date(),
+            isActive: agent.status === 'healthy'
         }));
         setAgentActivity(activity);
     };
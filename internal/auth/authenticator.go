@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"strings"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// Authenticator turns an Authorization header (or gRPC metadata) value
+// into the caller's identity. HTTP and gRPC share one instance.
+type Authenticator interface {
+	Authenticate(authorization string) (domain.Identity, error)
+}
+
+// Authenticate accepts "Bearer <jwt>"
+func (v *Verifier) Authenticate(authorization string) (domain.Identity, error) {
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found || token == "" {
+		return domain.Identity{}, ErrMissingToken
+	}
+
+	claims, err := v.Verify(token)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	return claims.Identity(), nil
+}
+
+// Static authenticates every caller as the same identity. It is only used
+// in development when no signing key is configured.
+type Static struct {
+	Identity domain.Identity
+}
+
+func (s Static) Authenticate(string) (domain.Identity, error) {
+	return s.Identity, nil
+}
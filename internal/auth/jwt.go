@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+var (
+	ErrMissingToken     = errors.New("missing bearer token")
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrMissingExpiry    = errors.New("token has no expiry")
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrMissingSubject   = errors.New("token has no subject")
+)
+
+// Claims are the registered claims we check plus the roles claim
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	TenantID  string   `json:"tenant,omitempty"`
+	Plan      string   `json:"plan,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+func (c *Claims) Identity() domain.Identity {
+	return domain.Identity{
+		UserID:   c.Subject,
+		TenantID: c.TenantID,
+		Plan:     c.Plan,
+		Roles:    c.Roles,
+	}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Verifier checks HS256 tokens against a shared secret and RS256 tokens
+// against a public key. An algorithm is only accepted when its key is
+// configured, so an RS256 public key can never be used as an HMAC secret.
+type Verifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+type Config struct {
+	Secret    []byte
+	PublicKey *rsa.PublicKey
+	Issuer    string        // required iss claim, if set
+	Leeway    time.Duration // allowed clock skew for exp and nbf
+}
+
+func NewVerifier(config Config) (*Verifier, error) {
+	if len(config.Secret) == 0 && config.PublicKey == nil {
+		return nil, fmt.Errorf("a JWT secret or RSA public key is required")
+	}
+
+	return &Verifier{
+		secret:    config.Secret,
+		publicKey: config.PublicKey,
+		issuer:    config.Issuer,
+		leeway:    config.Leeway,
+		now:       time.Now,
+	}, nil
+}
+
+// Verify checks the signature and time claims of a compact JWT
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := v.verifySignature(hdr.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := v.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (v *Verifier) verifySignature(alg string, signed string, signature []byte) error {
+	switch alg {
+	case "HS256":
+		if len(v.secret) == 0 {
+			return ErrUnsupportedAlg
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case "RS256":
+		if v.publicKey == nil {
+			return ErrUnsupportedAlg
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+
+	return ErrUnsupportedAlg
+}
+
+func (v *Verifier) validateClaims(claims *Claims) error {
+	now := v.now()
+
+	// A token without exp would be valid forever
+	if claims.ExpiresAt == 0 {
+		return ErrMissingExpiry
+	}
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if claims.Subject == "" {
+		return ErrMissingSubject
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// LoadRSAPublicKey reads a PEM encoded PKIX or PKCS#1 public key
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key %s is not PEM encoded", path)
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %v", err)
+	}
+
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an RSA key", path)
+	}
+	return key, nil
+}
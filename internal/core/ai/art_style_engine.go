@@ -5,6 +5,7 @@ type ArtStyleEngine struct {
 	filterPresets   map[string]FilterPreset
 	cameraEffects   map[string]CameraEffect
 	materialRenders map[string]MaterialRender
+	defaultStyle    string
 }
 
 type RenderStyle struct {
@@ -31,6 +32,7 @@ func NewArtStyleEngine() *ArtStyleEngine {
 		filterPresets:   make(map[string]FilterPreset),
 		cameraEffects:   make(map[string]CameraEffect),
 		materialRenders: make(map[string]MaterialRender),
+		defaultStyle:    "unreal_engine_5",
 	}
 	
 	engine.initializeRenderStyles()
@@ -130,12 +132,42 @@ func (a *ArtStyleEngine) initializeFilterPresets() {
 	}
 }
 
+// Styles lists the render style names ApplyArtStyle understands
+func (a *ArtStyleEngine) Styles() []string {
+	names := make([]string, 0, len(a.renderStyles))
+	for name := range a.renderStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Filters lists the filter preset names ApplyFilter understands
+func (a *ArtStyleEngine) Filters() []string {
+	names := make([]string, 0, len(a.filterPresets))
+	for name := range a.filterPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDefaultStyle picks the style used when none, or an unknown one, is
+// requested
+func (a *ArtStyleEngine) SetDefaultStyle(name string) error {
+	if _, exists := a.renderStyles[name]; !exists {
+		return fmt.Errorf("unknown art style %q", name)
+	}
+	a.defaultStyle = name
+	return nil
+}
+
 // ApplyArtStyle enhances prompt with specific rendering style
 func (a *ArtStyleEngine) ApplyArtStyle(prompt string, styleName string) string {
 	style, exists := a.renderStyles[styleName]
 	if !exists {
 		// Default to professional rendering
-		style = a.renderStyles["unreal_engine_5"]
+		style = a.renderStyles[a.defaultStyle]
 	}
 	
 	enhancements := []string{
@@ -5,6 +5,7 @@ type ArtStyleManager struct {
 	styleHierarchy   map[string][]string
 	conflictRules    map[string]ConflictRule
 	modernEras       map[string]ModernEra
+	detectionThreshold float64
 }
 
 type ArtStyleDefinition struct {
@@ -31,6 +32,7 @@ func NewArtStyleManager() *ArtStyleManager {
 		styleHierarchy:   make(map[string][]string),
 		conflictRules:    make(map[string]ConflictRule),
 		modernEras:       make(map[string]ModernEra),
+		detectionThreshold: 0.3,
 	}
 	
 	manager.initializeArtStyles()
@@ -176,6 +178,11 @@ func (a *ArtStyleManager) initializeModernEras() {
 	}
 }
 
+// SetDetectionThreshold sets the confidence a style needs to be detected
+func (a *ArtStyleManager) SetDetectionThreshold(threshold float64) {
+	a.detectionThreshold = threshold
+}
+
 // DetectAndPrioritizeStyle identifies and ranks art styles in prompt
 func (a *ArtStyleManager) DetectAndPrioritizeStyle(prompt string) *StyleAnalysis {
 	analysis := &StyleAnalysis{
@@ -185,7 +192,7 @@ func (a *ArtStyleManager) DetectAndPrioritizeStyle(prompt string) *StyleAnalysis
 	// Detect all mentioned styles
 	for styleName, styleDef := range a.styleDefinitions {
 		confidence := a.detectStyleConfidence(prompt, styleName, styleDef)
-		if confidence > 0.3 {
+		if confidence > a.detectionThreshold {
 			analysis.DetectedStyles[styleName] = confidence
 		}
 	}
@@ -45,11 +45,24 @@ func NewComicBookEngine() *ComicBookEngine {
 	}
 }
 
+// SetLoopSimilarity tunes how alike two panels may be before the safety
+// filter reports a generation loop
+func (c *ComicBookEngine) SetLoopSimilarity(threshold float64) {
+	c.safetyFilter.SetLoopSimilarity(threshold)
+}
+
+// ShareModerationHistory records the engine's safety violations in
+// history for a tenant workspace
+func (c *ComicBookEngine) ShareModerationHistory(history *ModerationHistory, tenantID string) {
+	c.safetyFilter.ShareHistory(history, tenantID)
+}
+
 // GenerateComicFromOutline creates complete comic from basic story outline
-func (c *ComicBookEngine) GenerateComicFromOutline(outline ComicOutline) (*ComicStory, error) {
+// for a user, who is recorded with any safety violation
+func (c *ComicBookEngine) GenerateComicFromOutline(userID string, outline ComicOutline) (*ComicStory, error) {
 	// Step 1: Safety check on story content
 	if err := c.safetyFilter.ValidateStoryContent(outline); err != nil {
-		return nil, fmt.Errorf("story content rejected: %v", err)
+		return nil, &SafetyRejection{Stage: "story content rejected", Err: err}
 	}
 
 	// Step 2: Develop characters with consistency
@@ -74,8 +87,8 @@ func (c *ComicBookEngine) GenerateComicFromOutline(outline ComicOutline) (*Comic
 	}
 	
 	// Final safety review
-	if err := c.safetyFilter.FinalComicReview(comic); err != nil {
-		return nil, fmt.Errorf("final safety check failed: %v", err)
+	if err := c.safetyFilter.FinalComicReview(userID, comic); err != nil {
+		return nil, &SafetyRejection{Stage: "final safety check failed", Err: err}
 	}
 	
 	return comic, nil
@@ -28,17 +28,23 @@ func NewConsciousUI() *ConsciousUI {
 
 // ProcessUserCommand handles expert-level commands like Google professionals
 func (c *ConsciousUI) ProcessUserCommand(command string, context UIState) *UIResponse {
+	return c.ProcessUserCommandWithState(command, &context)
+}
+
+// ProcessUserCommandWithState is ProcessUserCommand for conversations: the
+// context updates are written back to state so the next turn sees them
+func (c *ConsciousUI) ProcessUserCommandWithState(command string, state *UIState) *UIResponse {
 	// Analyze user intent and expertise level
 	intent := c.intentAnalyzer.AnalyzeExpertIntent(command)
 	
 	// Update context with new information
-	c.contextManager.UpdateContext(&context, command, intent)
+	c.contextManager.UpdateContext(state, command, intent)
 	
 	// Generate professional response
-	response := c.generateExpertResponse(command, intent, context)
+	response := c.generateExpertResponse(command, intent, *state)
 	
 	// Learn from interaction
-	c.memorySystem.RecordInteraction(command, response, context.UserExpertise)
+	c.memorySystem.RecordInteraction(command, response, state.UserExpertise)
 	
 	return response
 }
@@ -20,6 +20,12 @@ func NewFinalReviewAgent() *FinalReviewAgent {
 
 // ReviewAndFinalizePrompt is the final gatekeeper before generation
 func (f *FinalReviewAgent) ReviewAndFinalizePrompt(prompt string, context GenerationContext) (*FinalPrompt, error) {
+	return f.ReviewAndFinalizePromptTraced(prompt, context, nil)
+}
+
+// ReviewAndFinalizePromptTraced is ReviewAndFinalizePrompt with every
+// correction reported to tracer
+func (f *FinalReviewAgent) ReviewAndFinalizePromptTraced(prompt string, context GenerationContext, tracer PromptTracer) (*FinalPrompt, error) {
 	review := &PromptReview{
 		OriginalPrompt: prompt,
 		Context:        context,
@@ -38,7 +44,7 @@ func (f *FinalReviewAgent) ReviewAndFinalizePrompt(prompt string, context Genera
 	review.StyleCheck = f.styleEnforcer.EnforceStyleConsistency(prompt, context.Style)
 	
 	// Step 5: Generate final optimized prompt
-	finalPrompt, err := f.generateFinalPrompt(prompt, review)
+	finalPrompt, err := f.generateFinalPrompt(prompt, review, tracer)
 	if err != nil {
 		return nil, err
 	}
@@ -54,17 +60,21 @@ func (f *FinalReviewAgent) ReviewAndFinalizePrompt(prompt string, context Genera
 	}, nil
 }
 
-func (f *FinalReviewAgent) generateFinalPrompt(original string, review *PromptReview) (string, error) {
+func (f *FinalReviewAgent) generateFinalPrompt(original string, review *PromptReview, tracer PromptTracer) (string, error) {
 	prompt := original
 	
 	// Apply knowledge base corrections
 	if !review.KnowledgeCheck.IsAccurate {
+		before := prompt
 		prompt = f.knowledgeBase.CorrectTerminology(prompt, review.KnowledgeCheck.Issues)
+		traceStep(tracer, "final", "sports_knowledge", before, prompt)
 	}
 	
 	// Apply physics corrections
 	if !review.PhysicsCheck.IsValid {
+		before := prompt
 		prompt = f.physicsValidator.CorrectPhysics(prompt, review.PhysicsCheck.Issues)
+		traceStep(tracer, "final", "physics_validator", before, prompt)
 	}
 	
 	// Apply safety modifications
@@ -73,12 +83,15 @@ func (f *FinalReviewAgent) generateFinalPrompt(original string, review *PromptRe
 		if err != nil {
 			return "", err
 		}
+		traceStep(tracer, "final", "safety_analyzer", prompt, safePrompt)
 		prompt = safePrompt
 	}
 	
 	// Apply style enhancements
 	if !review.StyleCheck.IsConsistent {
+		before := prompt
 		prompt = f.styleEnforcer.EnhanceStyle(prompt, review.StyleCheck.Recommendations)
+		traceStep(tracer, "final", "style_enforcer", before, prompt)
 	}
 	
 	return prompt, nil
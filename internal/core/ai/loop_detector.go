@@ -16,6 +16,12 @@ func NewLoopDetector() *LoopDetector {
 	}
 }
 
+// SetSimilarityThreshold changes how alike two prompts must be to count
+// as a loop
+func (l *LoopDetector) SetSimilarityThreshold(threshold float64) {
+	l.similarityThreshold = threshold
+}
+
 // IsInLoop detects if generation is stuck in repetitive loop
 func (l *LoopDetector) IsInLoop(currentPrompt string) bool {
 	// Check if current prompt is too similar to recent ones
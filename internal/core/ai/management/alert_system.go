@@ -0,0 +1,77 @@
+package management
+
+import (
+	"sync"
+	"time"
+)
+
+// Alert reports that an agent's health check came back below healthy
+type Alert struct {
+	AgentID   string             `json:"agent_id"`
+	Status    string             `json:"status"`
+	Metrics   map[string]float64 `json:"metrics"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// AlertSystem fans agent health alerts out to listeners. Health checks run
+// on every status request, so an agent that stays degraded is re-alerted
+// only when its status changes or after the cooldown.
+type AlertSystem struct {
+	mu        sync.Mutex
+	cooldown  time.Duration
+	last      map[string]Alert
+	listeners []func(Alert)
+}
+
+func NewAlertSystem() *AlertSystem {
+	return &AlertSystem{
+		cooldown: time.Minute,
+		last:     make(map[string]Alert),
+	}
+}
+
+// TriggerAlert records an unhealthy check and notifies listeners unless it
+// repeats a recent alert
+func (a *AlertSystem) TriggerAlert(agentID string, status string, metrics map[string]float64) {
+	alert := Alert{
+		AgentID:   agentID,
+		Status:    status,
+		Metrics:   metrics,
+		Timestamp: time.Now().UTC(),
+	}
+
+	a.mu.Lock()
+	previous, seen := a.last[agentID]
+	if seen && previous.Status == status && alert.Timestamp.Sub(previous.Timestamp) < a.cooldown {
+		a.mu.Unlock()
+		return
+	}
+	a.last[agentID] = alert
+	listeners := a.listeners
+	a.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(alert)
+	}
+}
+
+// Subscribe calls fn for every alert. fn runs on the health-checking
+// goroutine and must not block.
+func (a *AlertSystem) Subscribe(fn func(Alert)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.listeners = append(a.listeners, fn)
+}
+
+// Active returns the last alert of every agent that has alerted
+func (a *AlertSystem) Active() []Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(a.last))
+	for _, alert := range a.last {
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
@@ -2,13 +2,27 @@ package management
 
 import (
 	"runtime"
+	"sync"
 	"time"
 )
 
+// outcomeWindow is how many recent calls an observed agent's error rate
+// and response time are measured over
+const outcomeWindow = 100
+
 type HealthMonitor struct {
 	metricsCollector *MetricsCollector
 	alertSystem      *AlertSystem
 	thresholds       HealthThresholds
+
+	mu       sync.Mutex
+	outcomes map[string][]outcome
+}
+
+// outcome is one observed call of an agent measured from traffic
+type outcome struct {
+	latency time.Duration
+	failed  bool
 }
 
 type HealthThresholds struct {
@@ -19,22 +33,82 @@ type HealthThresholds struct {
 	UptimeMinimum  time.Duration
 }
 
+// DefaultHealthThresholds are the limits a new monitor scores against
+func DefaultHealthThresholds() HealthThresholds {
+	return HealthThresholds{
+		MemoryUsage:   0.8,  // 80% max
+		CPULoad:       0.75, // 75% max  
+		ResponseTime:  2 * time.Second,
+		ErrorRate:     0.05, // 5% max
+		UptimeMinimum: 5 * time.Minute,
+	}
+}
+
 func NewHealthMonitor() *HealthMonitor {
 	return &HealthMonitor{
 		metricsCollector: NewMetricsCollector(),
 		alertSystem:      NewAlertSystem(),
-		thresholds: HealthThresholds{
-			MemoryUsage:   0.8,  // 80% max
-			CPULoad:       0.75, // 75% max  
-			ResponseTime:  2 * time.Second,
-			ErrorRate:     0.05, // 5% max
-			UptimeMinimum: 5 * time.Minute,
-		},
+		thresholds:       DefaultHealthThresholds(),
+		outcomes:         make(map[string][]outcome),
+	}
+}
+
+// SetThresholds replaces the limits; call it before the monitor is in use
+func (h *HealthMonitor) SetThresholds(thresholds HealthThresholds) {
+	h.thresholds = thresholds
+}
+
+// OnAlert calls fn for every alert raised by this monitor's agent checks
+func (h *HealthMonitor) OnAlert(fn func(Alert)) {
+	h.alertSystem.Subscribe(fn)
+}
+
+// RecordOutcome feeds one call into the agent's error rate and response
+// time, for agents such as image providers whose health shows in traffic
+func (h *HealthMonitor) RecordOutcome(agentID string, latency time.Duration, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	window := append(h.outcomes[agentID], outcome{latency: latency, failed: failed})
+	if len(window) > outcomeWindow {
+		window = window[len(window)-outcomeWindow:]
+	}
+	h.outcomes[agentID] = window
+}
+
+// observedMetrics are the recorded outcomes in the units the health score
+// expects: error_rate as a fraction and response_time in milliseconds
+func (h *HealthMonitor) observedMetrics(agentID string) map[string]float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	window := h.outcomes[agentID]
+	if len(window) == 0 {
+		return nil
+	}
+	failures, total := 0.0, time.Duration(0)
+	for _, o := range window {
+		if o.failed {
+			failures++
+		}
+		total += o.latency
+	}
+	return map[string]float64{
+		"error_rate":    failures / float64(len(window)),
+		"response_time": float64(total.Milliseconds()) / float64(len(window)),
 	}
 }
 
 func (h *HealthMonitor) CheckAgentHealth(agentID string, agent Agent) HealthStatus {
 	metrics := h.metricsCollector.CollectAgentMetrics(agentID)
+	if observed := h.observedMetrics(agentID); observed != nil {
+		if metrics == nil {
+			metrics = make(map[string]float64, len(observed))
+		}
+		for key, value := range observed {
+			metrics[key] = value
+		}
+	}
 	
 	health := HealthStatus{
 		Metrics:   metrics,
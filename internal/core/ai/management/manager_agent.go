@@ -1 +1,199 @@
+package management
 
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Agent is a supervised AI component of the generation pipeline
+type Agent interface {
+	ID() string
+	Name() string
+	Role() string
+}
+
+// AgentDescriptor is the static description of a pipeline agent
+type AgentDescriptor struct {
+	AgentID   string
+	AgentName string
+	AgentRole string
+}
+
+func (a AgentDescriptor) ID() string   { return a.AgentID }
+func (a AgentDescriptor) Name() string { return a.AgentName }
+func (a AgentDescriptor) Role() string { return a.AgentRole }
+
+type ManagerAgent struct {
+	mu                 sync.RWMutex
+	agents             map[string]Agent
+	healthMonitor      *HealthMonitor
+	performanceTracker *PerformanceTracker
+	startTime          time.Time
+}
+
+type AgentStatus struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Role        string  `json:"role"`
+	Status      string  `json:"status"`
+	HealthScore float64 `json:"health_score"`
+	Performance float64 `json:"performance"`
+}
+
+type AgentDetails struct {
+	AgentStatus
+	Health    HealthStatus `json:"health"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+type SystemStatus struct {
+	OverallHealth      float64       `json:"overall_health"`
+	AveragePerformance float64       `json:"average_performance"`
+	SystemLoad         float64       `json:"system_load"`
+	Uptime             time.Duration `json:"uptime"`
+	TotalAgents        int           `json:"total_agents"`
+	Agents             []AgentStatus `json:"agents"`
+}
+
+func NewManagerAgent() *ManagerAgent {
+	manager := &ManagerAgent{
+		agents:             make(map[string]Agent),
+		healthMonitor:      NewHealthMonitor(),
+		performanceTracker: NewPerformanceTracker(),
+		startTime:          time.Now(),
+	}
+
+	manager.registerCoreAgents()
+
+	return manager
+}
+
+func (m *ManagerAgent) registerCoreAgents() {
+	coreAgents := []AgentDescriptor{
+		{"nlu_engine", "NLU Engine", "intent and entity understanding"},
+		{"quality_agent", "Quality Inspector", "prompt quality assessment"},
+		{"error_agent", "Error Detective", "error analysis and recovery hints"},
+		{"suggestion_engine", "Suggestion Engine", "professional improvement suggestions"},
+		{"final_review", "Final Review Agent", "knowledge, physics, safety and style review"},
+		{"master_priority", "Master Priority Agent", "style, culture and conflict prioritization"},
+		{"expression_engine", "Character Expression Engine", "emotion and vibe enhancement"},
+		{"art_style_engine", "Art Style Engine", "render style and filter application"},
+		{"anatomy_engine", "3D Anatomy Engine", "pose physics and balance"},
+		{"scene_matcher", "Scene Matching Engine", "background and lighting matching"},
+		{"studio_knowledge", "Studio Knowledge", "studio lighting and diffusion setup"},
+		{"safety_filter", "Safety Filter", "content moderation"},
+	}
+
+	for _, agent := range coreAgents {
+		m.RegisterAgent(agent)
+	}
+}
+
+// RegisterAgent adds an agent to supervision, replacing one with the same ID
+func (m *ManagerAgent) RegisterAgent(agent Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.agents[agent.ID()] = agent
+}
+
+// ProviderAgentID is the agent ID an image provider is supervised under
+func ProviderAgentID(name string) string {
+	return "provider_" + name
+}
+
+// RegisterProvider supervises an image provider alongside the core agents
+func (m *ManagerAgent) RegisterProvider(name string) {
+	m.RegisterAgent(AgentDescriptor{ProviderAgentID(name), name + " image provider", "image generation"})
+}
+
+// ObserveProvider feeds an image provider call into the provider's health
+// and performance, so an outage shows in the agent status and alerts
+func (m *ManagerAgent) ObserveProvider(name string, latency time.Duration, err error) {
+	agentID := ProviderAgentID(name)
+	m.healthMonitor.RecordOutcome(agentID, latency, err != nil)
+	if err != nil {
+		m.performanceTracker.RecordFailure(agentID, ErrorData{})
+		return
+	}
+	m.performanceTracker.RecordSuccess(agentID, GenerationData{})
+}
+
+// GetSystemStatus summarizes the health and performance of every agent
+func (m *ManagerAgent) GetSystemStatus() *SystemStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := &SystemStatus{
+		Uptime:      time.Since(m.startTime),
+		TotalAgents: len(m.agents),
+		SystemLoad:  m.calculateSystemLoad(),
+		Agents:      make([]AgentStatus, 0, len(m.agents)),
+	}
+
+	totalHealth := 0.0
+	totalPerformance := 0.0
+	for id, agent := range m.agents {
+		health := m.healthMonitor.CheckAgentHealth(id, agent)
+		agentStatus := m.buildAgentStatus(agent, health)
+
+		totalHealth += agentStatus.HealthScore
+		totalPerformance += agentStatus.Performance
+		status.Agents = append(status.Agents, agentStatus)
+	}
+
+	if len(status.Agents) > 0 {
+		status.OverallHealth = totalHealth / float64(len(status.Agents))
+		status.AveragePerformance = totalPerformance / float64(len(status.Agents))
+	}
+
+	sort.Slice(status.Agents, func(i, j int) bool {
+		return status.Agents[i].ID < status.Agents[j].ID
+	})
+
+	return status
+}
+
+// OnAlert calls fn for every alert raised while checking agent health
+func (m *ManagerAgent) OnAlert(fn func(Alert)) {
+	m.healthMonitor.OnAlert(fn)
+}
+
+// GetAgentDetails returns the full health report for one agent, or nil if
+// the agent is not registered
+func (m *ManagerAgent) GetAgentDetails(agentID string) *AgentDetails {
+	m.mu.RLock()
+	agent, exists := m.agents[agentID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	health := m.healthMonitor.CheckAgentHealth(agentID, agent)
+
+	return &AgentDetails{
+		AgentStatus: m.buildAgentStatus(agent, health),
+		Health:      health,
+		CheckedAt:   time.Now(),
+	}
+}
+
+func (m *ManagerAgent) buildAgentStatus(agent Agent, health HealthStatus) AgentStatus {
+	return AgentStatus{
+		ID:          agent.ID(),
+		Name:        agent.Name(),
+		Role:        agent.Role(),
+		Status:      health.Status,
+		HealthScore: health.Score,
+		Performance: m.performanceTracker.EvaluatePerformance(agent.ID()),
+	}
+}
+
+// calculateSystemLoad approximates load as goroutines per core, capped at 1.0
+func (m *ManagerAgent) calculateSystemLoad() float64 {
+	load := float64(runtime.NumGoroutine()) / float64(runtime.NumCPU()*100)
+	return min(load, 1.0)
+}
@@ -38,8 +38,20 @@ func NewMasterPriorityAgent() *MasterPriorityAgent {
 	}
 }
 
+// SetStyleThreshold sets the confidence a style needs before the agent
+// weighs it
+func (m *MasterPriorityAgent) SetStyleThreshold(threshold float64) {
+	m.artStyleManager.SetDetectionThreshold(threshold)
+}
+
 // AnalyzeAndPrioritize is the master decision maker
 func (m *MasterPriorityAgent) AnalyzeAndPrioritize(prompt string) *MasterAnalysis {
+	return m.AnalyzeAndPrioritizeTraced(prompt, nil)
+}
+
+// AnalyzeAndPrioritizeTraced is AnalyzeAndPrioritize with every prompt
+// rewrite reported to tracer
+func (m *MasterPriorityAgent) AnalyzeAndPrioritizeTraced(prompt string, tracer PromptTracer) *MasterAnalysis {
 	analysis := &MasterAnalysis{
 		OriginalPrompt: prompt,
 	}
@@ -63,7 +75,7 @@ func (m *MasterPriorityAgent) AnalyzeAndPrioritize(prompt string) *MasterAnalysi
 	analysis.FinalPriorities = m.resolveConflictsAndPrioritize(analysis)
 	
 	// Step 7: Generate optimized prompt
-	analysis.OptimizedPrompt = m.generateOptimizedPrompt(prompt, analysis)
+	analysis.OptimizedPrompt = m.generateOptimizedPrompt(prompt, analysis, tracer)
 	
 	// Step 8: Quality enforcement
 	analysis.QualityCheck = m.qualityEnforcer.EnforceQuality(analysis.OptimizedPrompt, analysis)
@@ -92,23 +104,33 @@ func (m *MasterPriorityAgent) resolveConflictsAndPrioritize(analysis *MasterAnal
 	return priority
 }
 
-func (m *MasterPriorityAgent) generateOptimizedPrompt(original string, analysis *MasterAnalysis) string {
+func (m *MasterPriorityAgent) generateOptimizedPrompt(original string, analysis *MasterAnalysis, tracer PromptTracer) string {
 	optimized := original
 	
 	// Apply cultural adjustments
+	before := optimized
 	optimized = m.culturalEngine.ApplyCulturalContext(optimized, analysis.CulturalContext)
+	traceStep(tracer, "master", "cultural_engine", before, optimized)
 	
 	// Apply prioritized art style
+	before = optimized
 	optimized = m.artStyleManager.ApplyPrioritizedStyle(optimized, analysis.ArtStyle)
+	traceStep(tracer, "master", "art_style_manager", before, optimized)
 	
 	// Apply regional physics
+	before = optimized
 	optimized = m.applyRegionalPhysics(optimized, analysis.PhysicsRegion)
+	traceStep(tracer, "master", "regional_physics", before, optimized)
 	
 	// Apply background optimization
+	before = optimized
 	optimized = m.optimizeBackground(optimized, analysis.BackgroundType)
+	traceStep(tracer, "master", "background_optimizer", before, optimized)
 	
 	// Ensure no conflicts remain
+	before = optimized
 	optimized = m.conflictResolver.RemoveConflicts(optimized, analysis.FinalPriorities)
+	traceStep(tracer, "master", "conflict_resolver", before, optimized)
 	
 	return optimized
 }
@@ -0,0 +1,233 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type ModerationViolation struct {
+	Type      string    `json:"type"`
+	Content   string    `json:"content"`
+	UserID    string    `json:"user_id,omitempty"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Entries that shut a user out of generation and let them back in. They
+// are kept with the violations so a persisted log restores them.
+const (
+	ViolationShutdown       = "emergency_shutdown"
+	ViolationShutdownLifted = "shutdown_lifted"
+)
+
+// maxViolations is how many of the newest violations a history keeps in
+// memory; older ones remain only in a persisted log
+const maxViolations = 10000
+
+// ModerationHistory records safety violations. It is in-memory by default;
+// Persist attaches a JSON-lines log so violations survive restarts.
+// `geminizer admin moderation` reads them through the admin API.
+type ModerationHistory struct {
+	mu         sync.RWMutex
+	violations []ModerationViolation
+	logPath    string
+	listeners  []func(ModerationViolation)
+	shutDown   map[string]bool // by user ID
+}
+
+func NewModerationHistory() *ModerationHistory {
+	return &ModerationHistory{
+		violations: make([]ModerationViolation, 0),
+		shutDown:   make(map[string]bool),
+	}
+}
+
+// OpenModerationHistory loads the log at path and appends future violations to it
+func OpenModerationHistory(path string) (*ModerationHistory, error) {
+	history := NewModerationHistory()
+	if err := history.Persist(path); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Persist loads existing violations from path and logs new ones there
+func (m *ModerationHistory) Persist(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create moderation log directory: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("open moderation log: %v", err)
+	}
+
+	var loaded []ModerationViolation
+	if file != nil {
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var violation ModerationViolation
+			if err := json.Unmarshal(scanner.Bytes(), &violation); err != nil {
+				continue
+			}
+			loaded = append(loaded, violation)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.violations = append(loaded, m.violations...)
+	m.logPath = path
+	for _, violation := range m.violations {
+		m.trackShutdown(violation)
+	}
+	m.trim()
+
+	return nil
+}
+
+// RecordViolation stores a violation with no known user
+func (m *ModerationHistory) RecordViolation(violationType string, content string) {
+	m.RecordUserViolation("", violationType, content)
+}
+
+func (m *ModerationHistory) RecordUserViolation(userID string, violationType string, content string) {
+	m.RecordTenantViolation("", userID, violationType, content)
+}
+
+// RecordTenantViolation stores a violation by a user of a tenant workspace
+func (m *ModerationHistory) RecordTenantViolation(tenantID string, userID string, violationType string, content string) {
+	violation := ModerationViolation{
+		Type:      violationType,
+		Content:   content,
+		UserID:    userID,
+		TenantID:  tenantID,
+		Timestamp: time.Now().UTC(),
+	}
+
+	m.mu.Lock()
+	m.violations = append(m.violations, violation)
+	m.trackShutdown(violation)
+	m.trim()
+	if m.logPath != "" {
+		m.appendToLog(violation)
+	}
+	listeners := m.listeners
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(violation)
+	}
+}
+
+// ShutDownUser blocks a user from generating until the shutdown is lifted
+func (m *ModerationHistory) ShutDownUser(userID string, reason string) {
+	m.RecordUserViolation(userID, ViolationShutdown, reason)
+}
+
+// LiftShutdown lets a shut down user generate again
+func (m *ModerationHistory) LiftShutdown(userID string, reason string) {
+	m.RecordUserViolation(userID, ViolationShutdownLifted, reason)
+}
+
+// IsShutDown reports whether the user's latest shutdown is still in force
+func (m *ModerationHistory) IsShutDown(userID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.shutDown[userID]
+}
+
+// trackShutdown applies a shutdown entry; the caller holds m.mu
+func (m *ModerationHistory) trackShutdown(violation ModerationViolation) {
+	switch violation.Type {
+	case ViolationShutdown:
+		m.shutDown[violation.UserID] = true
+	case ViolationShutdownLifted:
+		delete(m.shutDown, violation.UserID)
+	}
+}
+
+// trim drops the oldest violations beyond maxViolations. Shutdowns are
+// tracked separately, so dropping their entries keeps them in force. It
+// trims an eighth past the limit at a time so recording stays cheap; the
+// caller holds m.mu.
+func (m *ModerationHistory) trim() {
+	if len(m.violations) <= maxViolations+maxViolations/8 {
+		return
+	}
+	m.violations = append([]ModerationViolation(nil), m.violations[len(m.violations)-maxViolations:]...)
+}
+
+// Subscribe calls fn for every violation recorded from now on. fn runs on
+// the recording goroutine and must not block.
+func (m *ModerationHistory) Subscribe(fn func(ModerationViolation)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, fn)
+}
+
+// appendToLog is best effort: moderation must never block generation
+func (m *ModerationHistory) appendToLog(violation ModerationViolation) {
+	data, err := json.Marshal(violation)
+	if err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(m.logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	file.Write(append(data, '\n'))
+}
+
+// GetViolations returns violations newest first, optionally only those
+// of one type and after a point in time
+func (m *ModerationHistory) GetViolations(violationType string, since time.Time) []ModerationViolation {
+	return m.GetTenantViolations("", violationType, since)
+}
+
+// GetTenantViolations is GetViolations for one tenant; "" means every tenant
+func (m *ModerationHistory) GetTenantViolations(tenantID string, violationType string, since time.Time) []ModerationViolation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var violations []ModerationViolation
+	for i := len(m.violations) - 1; i >= 0; i-- {
+		violation := m.violations[i]
+		if tenantID != "" && violation.TenantID != tenantID {
+			continue
+		}
+		if violationType != "" && violation.Type != violationType {
+			continue
+		}
+		if !since.IsZero() && violation.Timestamp.Before(since) {
+			continue
+		}
+		violations = append(violations, violation)
+	}
+
+	return violations
+}
+
+// CountByType tallies violations for dashboards and admin summaries
+func (m *ModerationHistory) CountByType() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, violation := range m.violations {
+		counts[violation.Type]++
+	}
+	return counts
+}
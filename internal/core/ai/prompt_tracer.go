@@ -0,0 +1,13 @@
+package ai
+
+// PromptTracer observes every rewrite an agent makes to a prompt
+type PromptTracer interface {
+	RecordStep(stage string, agent string, before string, after string)
+}
+
+// traceStep records a step when tracing is enabled
+func traceStep(tracer PromptTracer, stage string, agent string, before string, after string) {
+	if tracer != nil {
+		tracer.RecordStep(stage, agent, before, after)
+	}
+}
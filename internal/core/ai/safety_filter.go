@@ -6,6 +6,7 @@ type SafetyFilter struct {
 	loopDetector      *LoopDetector
 	explicitDetector  *ExplicitDetector
 	moderationHistory *ModerationHistory
+	tenantID          string // the workspace violations are recorded for
 }
 
 func NewSafetyFilter() *SafetyFilter {
@@ -18,6 +19,30 @@ func NewSafetyFilter() *SafetyFilter {
 	}
 }
 
+// NewSafetyFilterWithHistory shares a moderation history, e.g. a persisted one
+func NewSafetyFilterWithHistory(history *ModerationHistory) *SafetyFilter {
+	filter := NewSafetyFilter()
+	filter.moderationHistory = history
+	return filter
+}
+
+// ShareHistory records the filter's violations in history, e.g. the
+// server's, attributed to a tenant workspace
+func (s *SafetyFilter) ShareHistory(history *ModerationHistory, tenantID string) {
+	s.moderationHistory = history
+	s.tenantID = tenantID
+}
+
+// SetLoopSimilarity tunes the loop detector
+func (s *SafetyFilter) SetLoopSimilarity(threshold float64) {
+	s.loopDetector.SetSimilarityThreshold(threshold)
+}
+
+// ModerationHistory exposes recorded violations for admin tooling
+func (s *SafetyFilter) ModerationHistory() *ModerationHistory {
+	return s.moderationHistory
+}
+
 // ValidateStoryContent checks entire story for safety and ethics
 func (s *SafetyFilter) ValidateStoryContent(outline ComicOutline) error {
 	// Check for explicit content
@@ -42,28 +67,34 @@ func (s *SafetyFilter) ValidateStoryContent(outline ComicOutline) error {
 
 // ValidatePanelDescription checks individual panel descriptions
 func (s *SafetyFilter) ValidatePanelDescription(description string) error {
+	return s.ValidateUserPanelDescription("", description)
+}
+
+// ValidateUserPanelDescription checks a panel description written for a
+// user, who is recorded with any violation
+func (s *SafetyFilter) ValidateUserPanelDescription(userID string, description string) error {
 	// Detect explicit content
 	if s.explicitDetector.ContainsExplicitContent(description) {
-		s.moderationHistory.RecordViolation("explicit_content", description)
+		s.moderationHistory.RecordTenantViolation(s.tenantID, userID, "explicit_content", description)
 		return fmt.Errorf("panel description contains explicit content")
 	}
 	
 	// Detect infinite loops
 	if s.loopDetector.IsInLoop(description) {
-		s.moderationHistory.RecordViolation("generation_loop", description)
+		s.moderationHistory.RecordTenantViolation(s.tenantID, userID, "generation_loop", description)
 		return fmt.Errorf("detected generation loop - please rephrase")
 	}
 	
 	// Check for ethical concerns
 	if !s.ethicsEngine.IsContentAppropriate(description) {
-		s.moderationHistory.RecordViolation("ethical_concern", description)
+		s.moderationHistory.RecordTenantViolation(s.tenantID, userID, "ethical_concern", description)
 		return fmt.Errorf("content raises ethical concerns")
 	}
 	
 	// Content scanning for other issues
 	scanResult := s.contentScanner.ScanContent(description)
 	if !scanResult.IsSafe {
-		s.moderationHistory.RecordViolation(scanResult.IssueType, description)
+		s.moderationHistory.RecordTenantViolation(s.tenantID, userID, scanResult.IssueType, description)
 		return fmt.Errorf("content safety issue: %s", scanResult.IssueType)
 	}
 	
@@ -90,11 +121,12 @@ func (s *SafetyFilter) ValidateCharacterDescription(description string) error {
 	return nil
 }
 
-// FinalComicReview comprehensive safety check before generation
-func (s *SafetyFilter) FinalComicReview(comic *ComicStory) error {
+// FinalComicReview comprehensive safety check before generation; userID
+// is who the comic is for
+func (s *SafetyFilter) FinalComicReview(userID string, comic *ComicStory) error {
 	// Check all panels
 	for i, panel := range comic.Panels {
-		if err := s.ValidatePanelDescription(panel.Description); err != nil {
+		if err := s.ValidateUserPanelDescription(userID, panel.Description); err != nil {
 			return fmt.Errorf("panel %d: %v", i+1, err)
 		}
 		
@@ -0,0 +1,16 @@
+package ai
+
+// SafetyRejection marks content refused by a safety check, so callers can
+// tell it apart from an engine failure
+type SafetyRejection struct {
+	Stage string
+	Err   error
+}
+
+func (e *SafetyRejection) Error() string {
+	return e.Stage + ": " + e.Err.Error()
+}
+
+func (e *SafetyRejection) Unwrap() error {
+	return e.Err
+}
@@ -1,65 +0,0 @@
-package ai
-
-// ProcessSamplePrompt demonstrates how our AI agents handle real-world input
-func (p *PromptEngine) ProcessSamplePrompt() *SampleDemonstration {
-	sampleInput := `Character: A young woman with short, wavy pink hair, blue eyes, and a confident yet serene expression. She has an athletic but feminine physique.
-	Outfit: She is wearing a simple two-piece outfit consisting of a white bandeau (tube top) and a matching white mini-skirt.
-	Pose: Full lotus pose (Padmasana) with proper yoga form and Gyan Mudra hand gesture.`
-
-	// AI Agent 1: Prompt Curator Analysis
-	understanding := p.nluEngine.UnderstandPrompt(sampleInput)
-	
-	// AI Agent 2: Quality Assessment
-	quality := p.qualityAgent.AssessPromptQuality(sampleInput, understanding.Intent)
-	
-	// AI Agent 3: Professional Enhancement
-	enhancedPrompt := p.CuratePrompt(sampleInput, GenerationOptions{
-		Archetype: "confident",
-		Material:  "cotton",
-		Lighting:  "soft", 
-		Style:     "realistic",
-		Angle:     "front",
-		Quality:   "professional",
-	})
-
-	// AI Agent 4: Error Detection
-	errorAnalysis := p.errorAgent.AnalyzeError(sampleInput, enhancedPrompt, nil)
-
-	return &SampleDemonstration{
-		OriginalInput:    sampleInput,
-		NLUAnalysis:      understanding,
-		QualityAssessment: quality,
-		EnhancedPrompt:   enhancedPrompt,
-		ErrorAnalysis:    errorAnalysis,
-		AgentWorkflow:    p.getAgentWorkflow(),
-	}
-}
-
-func (p *PromptEngine) getAgentWorkflow() []AgentStep {
-	return []AgentStep{
-		{
-			Agent:      "NLU Engine",
-			Action:     "Understood yoga pose, feminine physique, specific colors",
-			Confidence: 0.95,
-			Timestamp:  time.Now(),
-		},
-		{
-			Agent:      "Quality Inspector", 
-			Action:     "Detected good specificity but missing professional terms",
-			Confidence: 0.88,
-			Timestamp:  time.Now(),
-		},
-		{
-			Agent:      "Prompt Curator",
-			Action:     "Added professional photography terms and lighting details",
-			Confidence: 0.92,
-			Timestamp:  time.Now(),
-		},
-		{
-			Agent:      "Error Detective",
-			Action:     "Verified pose is anatomically correct and physically possible",
-			Confidence: 0.96,
-			Timestamp:  time.Now(),
-		},
-	}
-}
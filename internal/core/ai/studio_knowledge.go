@@ -110,6 +110,12 @@ func (s *StudioKnowledge) initializeDiffuseSystems() {
 	}
 }
 
+// ShotTypes are the shot types the studio selectors recognise
+var ShotTypes = []string{"portrait", "headshot", "full_body", "beauty", "glamour", "fashion", "commercial", "product"}
+
+// Moods are the moods the studio selectors recognise
+var Moods = []string{"glamorous", "dramatic", "commercial", "clean", "moody", "elegant", "soft", "intense", "artistic"}
+
 // GetProfessionalStudioSetup suggests complete studio configuration
 func (s *StudioKnowledge) GetProfessionalStudioSetup(shotType string, mood string) *StudioSetup {
 	setup := &StudioSetup{
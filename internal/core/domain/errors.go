@@ -0,0 +1,56 @@
+package domain
+
+import "errors"
+
+// Error codes carried by AppError
+const (
+	ErrCodeQuotaExceeded  = "QUOTA_EXCEEDED"
+	ErrCodeRateLimited    = "RATE_LIMITED"
+	ErrCodeSafetyRejected = "SAFETY_REJECTED"
+	ErrCodeQAFailed       = "QA_FAILED"
+
+	ErrCodeInvalidRequest   = "INVALID_REQUEST"
+	ErrCodeValidationFailed = "VALIDATION_FAILED"
+	ErrCodeUnauthenticated  = "UNAUTHENTICATED"
+	ErrCodeForbidden        = "FORBIDDEN"
+	ErrCodeNotFound         = "NOT_FOUND"
+	ErrCodeConflict         = "CONFLICT"
+	ErrCodeTooLarge         = "PAYLOAD_TOO_LARGE"
+	ErrCodeUnavailable      = "UNAVAILABLE"
+	ErrCodeInternal         = "INTERNAL"
+)
+
+// AppError pairs an underlying error with a user-facing message and a
+// stable code clients can switch on
+type AppError struct {
+	Err     error  `json:"-"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+func NewAppError(err error, message string, code string) *AppError {
+	return &AppError{Err: err, Message: message, Code: code}
+}
+
+func (e *AppError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the first AppError in err's chain, or ""
+func ErrorCode(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// GenerationRecord is one completed generation as stored in history
+type GenerationRecord struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	Tier         string    `json:"tier"`
+	Prompt       string    `json:"prompt"`
+	FinalPrompt  string    `json:"final_prompt"`
+	Intent       string    `json:"intent"`
+	Style        string    `json:"style"`
+	QualityScore float64   `json:"quality_score"`
+	ImageURL     string    `json:"image_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// HistoryFilter narrows a history search. Zero values match everything.
+type HistoryFilter struct {
+	UserID     string
+	TenantID   string
+	From       time.Time
+	To         time.Time
+	Intent     string
+	Style      string
+	MinQuality float64
+	MaxQuality float64
+	Limit      int
+}
+
+// Matches reports whether a record satisfies every set field of the filter
+func (f HistoryFilter) Matches(record *GenerationRecord) bool {
+	if f.UserID != "" && record.UserID != f.UserID {
+		return false
+	}
+	if f.TenantID != "" && record.TenantID != f.TenantID {
+		return false
+	}
+	if !f.From.IsZero() && record.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && record.CreatedAt.After(f.To) {
+		return false
+	}
+	if f.Intent != "" && record.Intent != f.Intent {
+		return false
+	}
+	if f.Style != "" && record.Style != f.Style {
+		return false
+	}
+	if f.MinQuality > 0 && record.QualityScore < f.MinQuality {
+		return false
+	}
+	if f.MaxQuality > 0 && record.QualityScore > f.MaxQuality {
+		return false
+	}
+	return true
+}
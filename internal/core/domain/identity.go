@@ -0,0 +1,21 @@
+package domain
+
+// RoleAdmin grants access to agent internals, diagnostics and admin routes
+const RoleAdmin = "admin"
+
+// Identity is the authenticated caller behind a request
+type Identity struct {
+	UserID   string   `json:"user_id"`
+	TenantID string   `json:"tenant_id,omitempty"`
+	Plan     string   `json:"plan,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+func (i Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
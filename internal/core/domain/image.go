@@ -0,0 +1,38 @@
+package domain
+
+// AspectRatios lists every ratio a request may ask for. A provider supports
+// some subset, given in its capabilities.
+var AspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+
+// ImageRequest is one image for a provider to draw
+type ImageRequest struct {
+	Prompt         string
+	NegativePrompt string
+	AspectRatio    string
+}
+
+// Image is a drawn image. URL is either hosted by the provider or a data:
+// URL holding the image itself.
+type Image struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ProviderCapabilities describe the requests a provider accepts
+type ProviderCapabilities struct {
+	// MaxPromptLength is in characters; 0 means no limit
+	MaxPromptLength int      `json:"max_prompt_length"`
+	NegativePrompt  bool     `json:"negative_prompt"`
+	AspectRatios    []string `json:"aspect_ratios"`
+}
+
+// SupportsAspectRatio reports whether ratio is one of the provider's
+func (c ProviderCapabilities) SupportsAspectRatio(ratio string) bool {
+	for _, r := range c.AspectRatios {
+		if r == ratio {
+			return true
+		}
+	}
+	return false
+}
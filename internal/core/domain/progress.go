@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// ProgressEvent reports that one pipeline stage has finished
+type ProgressEvent struct {
+	Stage     string      `json:"stage"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Tenant is a workspace: one agency sharing the deployment. Its history,
+// characters, styles, policy and quota are kept apart from other tenants.
+type Tenant struct {
+	ID     string                 `json:"id"`
+	Name   string                 `json:"name"`
+	Plan   string                 `json:"plan,omitempty"`
+	Styles map[string]CustomStyle `json:"styles,omitempty"`
+	Policy SafetyPolicy           `json:"policy"`
+	// Pipelines replaces a tier's stage list for the workspace, by tier
+	Pipelines map[string][]string `json:"pipelines,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// CustomStyle is a tenant's own art style. Its prompt is appended to the
+// user's prompt and Base, if set, is the builtin style it refines.
+type CustomStyle struct {
+	Prompt string `json:"prompt" validate:"required,min=1,max=1000"`
+	Base   string `json:"base,omitempty" validate:"enum=style"`
+}
+
+// SafetyPolicy tightens the global safety checks for one tenant. It can
+// only add restrictions; the global checks always run as well.
+type SafetyPolicy struct {
+	BlockedTerms  []string `json:"blocked_terms,omitempty"`
+	BlockedStyles []string `json:"blocked_styles,omitempty"`
+}
+
+// Violations lists every rule of the policy the prompt and style break
+func (p SafetyPolicy) Violations(prompt string, style string) []string {
+	var violations []string
+
+	lower := strings.ToLower(prompt)
+	for _, term := range p.BlockedTerms {
+		if term != "" && strings.Contains(lower, strings.ToLower(term)) {
+			violations = append(violations, "blocked term: "+term)
+		}
+	}
+	for _, blocked := range p.BlockedStyles {
+		if style != "" && style == blocked {
+			violations = append(violations, "blocked style: "+blocked)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// TraceStep records the prompt before and after one agent touched it
+type TraceStep struct {
+	Stage   string    `json:"stage"`
+	Agent   string    `json:"agent"`
+	Before  string    `json:"before"`
+	After   string    `json:"after"`
+	Changed bool      `json:"changed"`
+	At      time.Time `json:"at"`
+}
+
+// PipelineTrace collects the steps of one generation in execution order.
+// A nil trace ignores every step, so callers never need to check.
+type PipelineTrace struct {
+	mu    sync.Mutex
+	Steps []TraceStep `json:"steps"`
+}
+
+func NewPipelineTrace() *PipelineTrace {
+	return &PipelineTrace{
+		Steps: make([]TraceStep, 0),
+	}
+}
+
+func (t *PipelineTrace) RecordStep(stage string, agent string, before string, after string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.Steps = append(t.Steps, TraceStep{
+		Stage:   stage,
+		Agent:   agent,
+		Before:  before,
+		After:   after,
+		Changed: before != after,
+		At:      time.Now().UTC(),
+	})
+}
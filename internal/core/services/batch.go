@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// BatchItem is one request in a batch, identified by a caller-chosen ID
+type BatchItem struct {
+	ID      string
+	Request TierRequest
+}
+
+// BatchResult is the outcome of one batch item. Exactly one of Result and
+// Error is set; Code is the error's AppError code, if it has one.
+type BatchResult struct {
+	ID         string      `json:"id"`
+	Result     *TierResult `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Code       string      `json:"code,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// GenerateBatch runs items through a bounded worker pool. onResult is
+// called once per item, from a single goroutine, in completion order.
+// A failed item never stops the rest of the batch.
+func (g *ServiceGraph) GenerateBatch(ctx context.Context, items []BatchItem, concurrency int, onResult func(BatchResult)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	work := make(chan BatchItem)
+	results := make(chan BatchResult)
+
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for item := range work {
+				results <- g.runBatchItem(ctx, item)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(work)
+		for _, item := range items {
+			select {
+			case work <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		onResult(result)
+	}
+}
+
+func (g *ServiceGraph) runBatchItem(ctx context.Context, item BatchItem) BatchResult {
+	start := time.Now()
+	result, err := g.Generate(ctx, item.Request)
+
+	batchResult := BatchResult{
+		ID:         item.ID,
+		Result:     result,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		batchResult.Error = err.Error()
+		batchResult.Code = domain.ErrorCode(err)
+	}
+
+	return batchResult
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"geminizer-enterprise/internal/core/ai"
+)
+
+// ComicRequest asks for a full comic from an outline
+type ComicRequest struct {
+	Outline    ai.ComicOutline `json:"outline" validate:"required"`
+	UserID     string          `json:"user_id,omitempty"`
+	WebhookURL string          `json:"webhook_url,omitempty"`
+}
+
+// ComicService runs the comic engine and reports the outcome like any
+// other generation. Each tenant gets its own engine, so characters and
+// the loop detector's recent prompts never cross workspaces.
+type ComicService struct {
+	mu       sync.Mutex
+	engines  map[string]*ai.ComicBookEngine
+	notifier Notifier
+	// loopSimilarity tunes new engines' loop detectors; 0 keeps the builtin
+	loopSimilarity float64
+	// moderation collects new engines' safety violations; nil keeps each
+	// engine's own history
+	moderation *ai.ModerationHistory
+}
+
+func NewComicService() *ComicService {
+	return &ComicService{
+		engines: make(map[string]*ai.ComicBookEngine),
+	}
+}
+
+// SetLoopSimilarity tunes how alike two panels may be before they count
+// as a generation loop. Engines already created keep their setting.
+func (s *ComicService) SetLoopSimilarity(threshold float64) error {
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("loop_similarity must be between 0 and 1, got %v", threshold)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loopSimilarity = threshold
+	return nil
+}
+
+// engine returns the caller's tenant engine; callers outside any tenant
+// share the "" engine
+func (s *ComicService) engine(ctx context.Context) *ai.ComicBookEngine {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID := TenantIDFrom(ctx)
+	engine, ok := s.engines[tenantID]
+	if !ok {
+		engine = ai.NewComicBookEngine()
+		if s.loopSimilarity != 0 {
+			engine.SetLoopSimilarity(s.loopSimilarity)
+		}
+		if s.moderation != nil {
+			engine.ShareModerationHistory(s.moderation, tenantID)
+		}
+		s.engines[tenantID] = engine
+	}
+	return engine
+}
+
+func (s *ComicService) GenerateComic(ctx context.Context, req ComicRequest) (*ai.ComicStory, error) {
+	if identity, ok := IdentityFrom(ctx); ok {
+		req.UserID = identity.UserID
+	}
+
+	comic, err := s.engine(ctx).GenerateComicFromOutline(req.UserID, req.Outline)
+
+	if s.notifier != nil {
+		eventType, data := EventComicCompleted, interface{}(comic)
+		switch {
+		case isSafetyRejection(err):
+			eventType, data = EventComicSafetyRejected, map[string]string{"error": err.Error()}
+		case err != nil:
+			eventType, data = EventComicFailed, map[string]string{"error": err.Error()}
+		}
+		s.notifier.Notify(ctx, newOutcomeEvent(ctx, eventType, req.WebhookURL, req.UserID, data))
+	}
+
+	return comic, err
+}
@@ -23,36 +23,60 @@ func NewEnhancedImageGenerator(repo HistoryRepository, logger Logger) *EnhancedI
 
 // GenerateWithAnalysis provides enhanced generation with AI analysis
 func (e *EnhancedImageGenerator) GenerateWithAnalysis(ctx context.Context, req domain.GenerationRequest) (*domain.EnhancedGenerationResponse, error) {
-	// Step 1: Natural Language Understanding
-	promptUnderstanding := e.nluEngine.UnderstandPrompt(req.UserPrompt)
-	
-	// Step 2: Quality Assessment
-	qualityAssessment := e.qualityAgent.AssessPromptQuality(req.UserPrompt, promptUnderstanding.Intent)
+	// Step 1: Prompt analysis
+	analysis := e.analyze(ctx, req)
 	
-	// Step 3: Generate image (original logic)
-	response, err := e.ImageGenerator.Generate(ctx, req)
+	// Step 2: Generate image (original logic)
+	response, err := e.draw(ctx, req)
 	if err != nil {
-		// Use error agent to provide helpful error messages
-		errorAnalysis := e.errorAgent.AnalyzeError(req.UserPrompt, "", nil)
-		return nil, domain.NewAppError(err, errorAnalysis.Suggestions[0].Description, "ENHANCED_ERROR")
+		return nil, e.explainFailure(req, err)
 	}
+	recordStep(ctx, "enhanced", "image_generator", req.UserPrompt, response.EnrichedPrompt)
 	
-	// Step 4: Generate intelligent suggestions
-	suggestions := e.suggestionAgent.GenerateSuggestions(req.UserPrompt, req.Options)
-	
-	// Step 5: Create enhanced response
+	// Step 3: Create enhanced response
 	enhancedResponse := &domain.EnhancedGenerationResponse{
 		GenerationResponse: *response,
-		Analysis: &domain.GenerationAnalysis{
-			Intent:            promptUnderstanding.Intent,
-			Entities:          promptUnderstanding.Entities,
-			QualityScore:      qualityAssessment.Score,
-			Strengths:         qualityAssessment.Strengths,
-			ImprovementAreas:  qualityAssessment.Weaknesses,
-			Suggestions:       suggestions,
-			ProfessionalLevel: qualityAssessment.ProfessionalLevel,
-		},
+		Analysis:           analysis,
 	}
 	
 	return enhancedResponse, nil
 }
+
+// analyze understands and scores the prompt without drawing it, so tiers
+// that review the prompt first can still report the analysis
+func (e *EnhancedImageGenerator) analyze(ctx context.Context, req domain.GenerationRequest) *domain.GenerationAnalysis {
+	// Natural Language Understanding
+	promptUnderstanding := e.nluEngine.UnderstandPrompt(req.UserPrompt)
+	
+	// Quality Assessment
+	qualityAssessment := e.qualityAgent.AssessPromptQuality(req.UserPrompt, promptUnderstanding.Intent)
+	reportProgress(ctx, StagePromptAnalysis, "Prompt analysis complete", map[string]interface{}{
+		"intent":        promptUnderstanding.Intent,
+		"quality_score": qualityAssessment.Score,
+	})
+	
+	// Generate intelligent suggestions
+	suggestions := e.suggestionAgent.GenerateSuggestions(req.UserPrompt, req.Options)
+	
+	return &domain.GenerationAnalysis{
+		Intent:            promptUnderstanding.Intent,
+		Entities:          promptUnderstanding.Entities,
+		QualityScore:      qualityAssessment.Score,
+		Strengths:         qualityAssessment.Strengths,
+		ImprovementAreas:  qualityAssessment.Weaknesses,
+		Suggestions:       suggestions,
+		ProfessionalLevel: qualityAssessment.ProfessionalLevel,
+	}
+}
+
+// explainFailure turns an image backend error into a helpful message,
+// keeping the code of an error that already carries one, such as every
+// image provider being unavailable
+func (e *EnhancedImageGenerator) explainFailure(req domain.GenerationRequest, err error) error {
+	if domain.ErrorCode(err) != "" {
+		return err
+	}
+	// Use error agent to provide helpful error messages
+	errorAnalysis := e.errorAgent.AnalyzeError(req.UserPrompt, "", nil)
+	return domain.NewAppError(err, errorAnalysis.Suggestions[0].Description, "ENHANCED_ERROR")
+}
@@ -22,13 +22,22 @@ func NewEnterprise3DGenerationService(repo HistoryRepository, logger Logger) *En
 func (e *Enterprise3DGenerationService) Generate3DProfessional(ctx context.Context, req domain.Enterprise3DRequest) (*domain.Enterprise3DResponse, error) {
 	// Step 1: 3D Pose Analysis and Enhancement
 	poseEnhanced := e.anatomyEngine.EnhancePoseDescription(req.UserPrompt)
+	recordStep(ctx, "3d", "anatomy_engine", req.UserPrompt, poseEnhanced)
+	reportProgress(ctx, StagePoseAnalysis, "3D pose analysis complete", nil)
 	
 	// Step 2: Scene Background Matching
 	sceneEnhanced := e.sceneMatcher.EnhanceSceneDescription(poseEnhanced)
+	recordStep(ctx, "3d", "scene_matcher", poseEnhanced, sceneEnhanced)
+	reportProgress(ctx, StageSceneMatch, "Scene background matched", nil)
 	
 	// Step 3: Studio Setup Application
 	studioSetup := e.studioKnowledge.GetProfessionalStudioSetup(req.ShotType, req.Mood)
 	studioEnhanced := e.applyStudioSetup(sceneEnhanced, studioSetup)
+	recordStep(ctx, "3d", "studio_knowledge", sceneEnhanced, studioEnhanced)
+	reportProgress(ctx, StageStudioSetup, "Studio setup applied", map[string]interface{}{
+		"shot_type": req.ShotType,
+		"mood":      req.Mood,
+	})
 	
 	// Step 4: Enterprise Generation
 	enterpriseReq := domain.EnterpriseRequest{
@@ -20,13 +20,21 @@ func NewEnterpriseGenerationService(repo HistoryRepository, logger Logger) *Ente
 func (e *EnterpriseGenerationService) GenerateEnterpriseGrade(ctx context.Context, req domain.EnterpriseRequest) (*domain.EnterpriseResponse, error) {
 	// Step 1: Enhance character expressions and emotions
 	characterEnhanced := e.expressionEngine.EnhanceCharacterDescription(req.UserPrompt)
+	recordStep(ctx, "enterprise", "expression_engine", req.UserPrompt, characterEnhanced)
 	
 	// Step 2: Apply art style and rendering
 	styleEnhanced := e.artStyleEngine.ApplyArtStyle(characterEnhanced, req.Style)
+	recordStep(ctx, "enterprise", "art_style_engine", characterEnhanced, styleEnhanced)
 	
 	if req.Filter != "" {
-		styleEnhanced = e.artStyleEngine.ApplyFilter(styleEnhanced, req.Filter)
+		filtered := e.artStyleEngine.ApplyFilter(styleEnhanced, req.Filter)
+		recordStep(ctx, "enterprise", "art_style_filter", styleEnhanced, filtered)
+		styleEnhanced = filtered
 	}
+	reportProgress(ctx, StageStyleApplied, "Art style applied", map[string]interface{}{
+		"style":  req.Style,
+		"filter": req.Filter,
+	})
 	
 	// Step 3: Create generation request
 	genRequest := domain.GenerationRequest{
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// Event types delivered to notifiers
+const (
+	EventGenerationCompleted      = "generation.completed"
+	EventGenerationFailed         = "generation.failed"
+	EventGenerationSafetyRejected = "generation.safety_rejected"
+	EventComicCompleted           = "comic.completed"
+	EventComicFailed              = "comic.failed"
+	EventComicSafetyRejected      = "comic.safety_rejected"
+)
+
+// OutcomeEvent describes how a generation or comic run ended
+type OutcomeEvent struct {
+	Type       string
+	WebhookURL string // per-request target; empty means the tenant's
+	UserID     string
+	TenantID   string
+	JobID      string
+	Data       interface{}
+}
+
+// Notifier is told about every finished run, e.g. to deliver webhooks.
+// Notify must not block the caller.
+type Notifier interface {
+	Notify(ctx context.Context, event OutcomeEvent)
+}
+
+type jobIDKey struct{}
+
+// WithJobID tags events raised under ctx with the job that ran them
+func WithJobID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, jobIDKey{}, id)
+}
+
+func JobIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(jobIDKey{}).(string)
+	return id
+}
+
+// isSafetyRejection covers both the final review gate and the comic
+// engine's safety filter
+func isSafetyRejection(err error) bool {
+	var rejection *ai.SafetyRejection
+	return domain.ErrorCode(err) == domain.ErrCodeSafetyRejected || errors.As(err, &rejection)
+}
+
+func newOutcomeEvent(ctx context.Context, eventType string, webhookURL string, userID string, data interface{}) OutcomeEvent {
+	event := OutcomeEvent{
+		Type:       eventType,
+		WebhookURL: webhookURL,
+		UserID:     userID,
+		JobID:      JobIDFrom(ctx),
+		Data:       data,
+	}
+	if identity, ok := IdentityFrom(ctx); ok {
+		event.TenantID = identity.TenantID
+	}
+	return event
+}
@@ -16,46 +16,58 @@ func NewFinalGenerationService(repo HistoryRepository, logger Logger) *FinalGene
 
 // GenerateWithFinalReview is the ultimate generation endpoint
 func (f *FinalGenerationService) GenerateWithFinalReview(ctx context.Context, req domain.GenerationRequest) (*domain.FinalGenerationResponse, error) {
-	// Step 1: Initial enhancement
-	enhancedResponse, err := f.imageGenerator.GenerateWithAnalysis(ctx, req)
-	if err != nil {
-		return nil, err
-	}
+	// Step 1: Prompt analysis; the image waits for the approved prompt
+	analysis := f.imageGenerator.analyze(ctx, req)
 	
-	// Step 2: Final review by expert agent
-	finalPrompt, err := f.finalReview.ReviewAndFinalizePrompt(
-		enhancedResponse.EnrichedPrompt,
+	// Step 2: Final review by expert agent of the enriched prompt
+	finalPrompt, err := f.finalReview.ReviewAndFinalizePromptTraced(
+		f.imageGenerator.enrich(ctx, req),
 		ai.GenerationContext{
 			Style:    req.Options.Style,
 			Intent:   f.detectIntent(req.UserPrompt),
 			UserLevel: f.assessUserLevel(ctx, req.UserID),
 		},
+		promptTracer(ctx),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("final review failed: %v", err)
 	}
 	
+	reportProgress(ctx, StageFinalReview, "Final review complete", map[string]interface{}{
+		"approved":   finalPrompt.Review.Approved,
+		"confidence": finalPrompt.Confidence,
+	})
+	
 	if !finalPrompt.Review.Approved {
-		return nil, fmt.Errorf("prompt not approved: %v", finalPrompt.Review.GetIssues())
+		return nil, domain.NewAppError(nil, fmt.Sprintf("prompt not approved: %v", finalPrompt.Review.GetIssues()), domain.ErrCodeSafetyRejected)
 	}
 	
 	// Step 3: Quality assurance
 	qualityCheck := f.qualityAssurance.VerifyQuality(finalPrompt.Final)
+	reportProgress(ctx, StageQualityAssurance, "Quality assurance complete", map[string]interface{}{
+		"passed": qualityCheck.Passed,
+		"issues": qualityCheck.Issues,
+	})
 	if !qualityCheck.Passed {
-		return nil, fmt.Errorf("quality assurance failed: %v", qualityCheck.Issues)
+		return nil, domain.NewAppError(nil, fmt.Sprintf("quality assurance failed: %v", qualityCheck.Issues), domain.ErrCodeQAFailed)
 	}
 	
-	// Step 4: Final generation with approved prompt
+	// Step 4: Final generation with approved prompt, the tier's only image.
+	// The prompt is already enriched, so it is drawn as written.
 	finalRequest := req
 	finalRequest.UserPrompt = finalPrompt.Final
 	
-	finalResult, err := f.imageGenerator.GenerateImage(finalRequest.UserPrompt, finalRequest.Options)
+	response, err := f.imageGenerator.drawApproved(ctx, finalRequest)
 	if err != nil {
-		return nil, err
+		return nil, f.imageGenerator.explainFailure(finalRequest, err)
 	}
+	recordStep(ctx, "final", "image_generator", finalRequest.UserPrompt, response.EnrichedPrompt)
 	
 	return &domain.FinalGenerationResponse{
-		GenerationResponse: *enhancedResponse,
+		GenerationResponse: domain.EnhancedGenerationResponse{
+			GenerationResponse: *response,
+			Analysis:           analysis,
+		},
 		FinalPrompt:        finalPrompt.Final,
 		Review:             finalPrompt.Review,
 		QualityCheck:       qualityCheck,
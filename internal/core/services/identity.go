@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+type identityKey struct{}
+
+// WithIdentity attaches the authenticated caller to ctx. Services prefer it
+// over any user ID carried in the request body.
+func WithIdentity(ctx context.Context, identity domain.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFrom returns the caller attached to ctx, if any
+func IdentityFrom(ctx context.Context) (domain.Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(domain.Identity)
+	return identity, ok
+}
+
+// TenantIDFrom returns the caller's tenant, or "" for callers outside any
+// tenant, who share one default workspace
+func TenantIDFrom(ctx context.Context) string {
+	identity, _ := IdentityFrom(ctx)
+	return identity.TenantID
+}
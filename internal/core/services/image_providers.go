@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// ImageProviderInfo describes a registered provider to clients
+type ImageProviderInfo struct {
+	Name         string                      `json:"name"`
+	Default      bool                        `json:"default"`
+	Capabilities domain.ProviderCapabilities `json:"capabilities"`
+	// Breaker is closed, open or half_open
+	Breaker string `json:"breaker"`
+	// Health is the provider's recent success rate
+	Health float64 `json:"health"`
+}
+
+// imageSelection is how a request draws: the provider it prefers, whether
+// others may step in, and the settings only a provider understands
+type imageSelection struct {
+	router         *providerRouter
+	preferred      string
+	failover       bool
+	negativePrompt string
+	aspectRatio    string
+}
+
+// supports reports whether a provider can honour the selection's settings
+func (s *imageSelection) supports(capabilities domain.ProviderCapabilities) bool {
+	if s.negativePrompt != "" && !capabilities.NegativePrompt {
+		return false
+	}
+	return s.aspectRatio == "" || capabilities.SupportsAspectRatio(s.aspectRatio)
+}
+
+type imageSelectionKey struct{}
+
+func withImageSelection(ctx context.Context, selection *imageSelection) context.Context {
+	return context.WithValue(ctx, imageSelectionKey{}, selection)
+}
+
+func imageSelectionFrom(ctx context.Context) *imageSelection {
+	selection, _ := ctx.Value(imageSelectionKey{}).(*imageSelection)
+	return selection
+}
+
+// SetImageProviders registers the backends a request can select by name.
+// fallback draws requests that name none and fails over to the others
+// when it cannot; AutoProvider picks by health alone, and an empty
+// fallback keeps such requests on the builtin image generator.
+func (g *ServiceGraph) SetImageProviders(providers []ImageProvider, fallback string, routing RoutingConfig) error {
+	byName := make(map[string]ImageProvider, len(providers))
+	for _, provider := range providers {
+		if provider.Name() == AutoProvider {
+			return fmt.Errorf("image provider name %q is reserved", AutoProvider)
+		}
+		if _, taken := byName[provider.Name()]; taken {
+			return fmt.Errorf("image provider %q registered more than once", provider.Name())
+		}
+		byName[provider.Name()] = provider
+	}
+	if _, ok := byName[fallback]; fallback != "" && fallback != AutoProvider && !ok {
+		return fmt.Errorf("default image provider %q is not configured", fallback)
+	}
+	if routing.Timeout <= 0 || routing.MaxAttempts < 1 || routing.FailureThreshold < 1 || routing.OpenDuration <= 0 || routing.RetryBudget < 0 {
+		return fmt.Errorf("image routing: timeout, attempts, failure threshold and open duration must be positive and the retry budget not negative")
+	}
+
+	g.router = newProviderRouter(byName, routing, g.providerObserver)
+	g.defaultProvider = fallback
+	return nil
+}
+
+// SetProviderObserver reports the outcome of every image provider call
+// to observer
+func (g *ServiceGraph) SetProviderObserver(observer ProviderObserver) {
+	g.providerObserver = observer
+	if g.router != nil {
+		g.router.observer = observer
+	}
+}
+
+// ImageProviders lists the registered providers by name
+func (g *ServiceGraph) ImageProviders() []ImageProviderInfo {
+	if g.router == nil {
+		return []ImageProviderInfo{}
+	}
+
+	infos := make([]ImageProviderInfo, 0, len(g.router.routes))
+	for name, route := range g.router.routes {
+		breaker, health := route.status()
+		infos = append(infos, ImageProviderInfo{
+			Name:         name,
+			Default:      name == g.defaultProvider,
+			Capabilities: route.provider.Capabilities(),
+			Breaker:      breaker,
+			Health:       health,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// selectImage resolves how req draws and checks its provider settings. A
+// provider the request names is used alone; the default may fail over. It
+// returns nil when the builtin image generator draws.
+func (g *ServiceGraph) selectImage(req TierRequest) (*imageSelection, error) {
+	selection := &imageSelection{
+		preferred:      req.Provider,
+		negativePrompt: req.NegativePrompt,
+		aspectRatio:    req.AspectRatio,
+	}
+	if selection.preferred == "" {
+		selection.preferred, selection.failover = g.defaultProvider, true
+	}
+	if selection.preferred == "" || g.router == nil {
+		if req.Provider != "" {
+			return nil, fmt.Errorf("unknown image provider %q", req.Provider)
+		}
+		if req.NegativePrompt != "" || req.AspectRatio != "" {
+			return nil, fmt.Errorf("negative_prompt and aspect_ratio need an image provider")
+		}
+		return nil, nil
+	}
+	selection.router = g.router
+
+	if selection.preferred == AutoProvider {
+		selection.preferred, selection.failover = "", true
+	} else if _, ok := g.router.routes[selection.preferred]; !ok {
+		return nil, fmt.Errorf("unknown image provider %q", selection.preferred)
+	}
+
+	if !selection.failover {
+		capabilities := g.router.routes[selection.preferred].provider.Capabilities()
+		if req.NegativePrompt != "" && !capabilities.NegativePrompt {
+			return nil, fmt.Errorf("image provider %q does not support negative prompts", selection.preferred)
+		}
+		if req.AspectRatio != "" && !capabilities.SupportsAspectRatio(req.AspectRatio) {
+			return nil, fmt.Errorf("image provider %q supports aspect ratios %v", selection.preferred, capabilities.AspectRatios)
+		}
+		return selection, nil
+	}
+
+	for _, route := range g.router.routes {
+		if selection.supports(route.provider.Capabilities()) {
+			return selection, nil
+		}
+	}
+	return nil, fmt.Errorf("no image provider supports this negative_prompt and aspect_ratio")
+}
+
+type dryRunKey struct{}
+
+// WithDryRun runs every stage of a generation except the image backend
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx asks for a generation without an image
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// enrich is the prompt draw would send, worked up by the builtin image
+// generator but not drawn, so a tier can review it before the one image
+// is made. Providers draw the prompt as written.
+func (e *EnhancedImageGenerator) enrich(ctx context.Context, req domain.GenerationRequest) string {
+	if imageSelectionFrom(ctx) != nil {
+		return req.UserPrompt
+	}
+	return e.ImageGenerator.EnrichPrompt(req.UserPrompt, req.Options)
+}
+
+// draw renders the request's prompt through the provider router, or with
+// the builtin image generator when no provider is selected. It is the
+// only path to the image backend, so each tier draws its image here once.
+// A dry run returns the prompt it would have drawn and no image.
+func (e *EnhancedImageGenerator) draw(ctx context.Context, req domain.GenerationRequest) (*domain.GenerationResponse, error) {
+	return e.render(ctx, req, e.ImageGenerator.Generate)
+}
+
+// drawApproved is draw for a prompt that enrich already worked up and a
+// review passed: the builtin image generator renders it as written, so
+// the image is made from exactly the prompt that was approved
+func (e *EnhancedImageGenerator) drawApproved(ctx context.Context, req domain.GenerationRequest) (*domain.GenerationResponse, error) {
+	return e.render(ctx, req, e.ImageGenerator.Render)
+}
+
+// render is draw with the builtin image generator's drawing step given
+func (e *EnhancedImageGenerator) render(ctx context.Context, req domain.GenerationRequest, builtin func(context.Context, domain.GenerationRequest) (*domain.GenerationResponse, error)) (*domain.GenerationResponse, error) {
+	if IsDryRun(ctx) {
+		reportProgress(ctx, StageImageReady, "Dry run complete; no image drawn", map[string]interface{}{
+			"dry_run": true,
+		})
+		return &domain.GenerationResponse{EnrichedPrompt: req.UserPrompt}, nil
+	}
+
+	var response *domain.GenerationResponse
+	if selection := imageSelectionFrom(ctx); selection != nil {
+		image, sent, err := selection.router.generate(ctx, selection, req.UserPrompt)
+		if err != nil {
+			return nil, err
+		}
+		response = &domain.GenerationResponse{
+			ImageURL:       image.URL,
+			EnrichedPrompt: sent,
+		}
+	} else {
+		var err error
+		if response, err = builtin(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	reportProgress(ctx, StageImageReady, "Image generated", map[string]interface{}{
+		"image_url": response.ImageURL,
+	})
+	return response, nil
+}
+
+// fitPrompt shortens an enriched prompt to a provider's limit, cutting at
+// the last separator so no keyword is left half written
+func fitPrompt(prompt string, limit int) string {
+	runes := []rune(prompt)
+	if limit <= 0 || len(runes) <= limit {
+		return prompt
+	}
+
+	cut := string(runes[:limit])
+	if i := strings.LastIndexAny(cut, ", "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ", ")
+}
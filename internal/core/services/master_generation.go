@@ -16,18 +16,36 @@ func NewMasterGenerationService(repo HistoryRepository, logger Logger) *MasterGe
 
 // GenerateWithMasterControl is the ultimate generation endpoint
 func (m *MasterGenerationService) GenerateWithMasterControl(ctx context.Context, req domain.MasterRequest) (*domain.MasterResponse, error) {
+	return m.generate(ctx, req, "")
+}
+
+// generate runs the master pipeline. A style in the request's options
+// replaces the one the master analysis picked, and filter is applied on
+// top of it by the enterprise tiers.
+func (m *MasterGenerationService) generate(ctx context.Context, req domain.MasterRequest, filter string) (*domain.MasterResponse, error) {
 	// Step 1: Master analysis and prioritization
-	masterAnalysis := m.masterAgent.AnalyzeAndPrioritize(req.UserPrompt)
+	masterAnalysis := m.masterAgent.AnalyzeAndPrioritizeTraced(req.UserPrompt, promptTracer(ctx))
+	
+	reportProgress(ctx, StageMasterAnalysis, "Master analysis complete", map[string]interface{}{
+		"primary_style": masterAnalysis.ArtStyle.PrimaryStyle,
+		"confidence":    masterAnalysis.FinalPriorities.Confidence,
+		"passed":        masterAnalysis.QualityCheck.Passed,
+	})
 	
 	if !masterAnalysis.QualityCheck.Passed {
 		return nil, fmt.Errorf("master quality check failed: %v", masterAnalysis.QualityCheck.Issues)
 	}
 	
 	// Step 2: Create enterprise request with optimized prompt
+	style := masterAnalysis.ArtStyle.PrimaryStyle
+	if req.Options.Style != "" {
+		style = req.Options.Style
+	}
 	enterpriseReq := domain.Enterprise3DRequest{
 		UserPrompt: masterAnalysis.OptimizedPrompt,
 		Options:    req.Options,
-		Style:      masterAnalysis.ArtStyle.PrimaryStyle,
+		Style:      style,
+		Filter:     filter,
 		ShotType:   req.ShotType,
 		Mood:       req.Mood,
 		UserID:     req.UserID,
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// Builtin stage names. Every step of the tier services is available as a
+// stage, plus cultural analysis, which otherwise only runs inside the
+// master agent.
+const (
+	StageNameMaster     = "master_analysis"
+	StageNameCultural   = "cultural_analysis"
+	StageNamePose       = "pose_analysis"
+	StageNameScene      = "scene_match"
+	StageNameStudio     = "studio_setup"
+	StageNameExpression = "expression"
+	StageNameArtStyle   = "art_style"
+	StageNameAnalysis   = "prompt_analysis"
+	StageNameReview     = "final_review"
+	StageNameQA         = "quality_assurance"
+	StageNameImage      = "image"
+)
+
+// StageNames lists the builtin stages in alphabetical order
+var StageNames = []string{
+	StageNameArtStyle, StageNameCultural, StageNameExpression, StageNameReview,
+	StageNameImage, StageNameMaster, StageNamePose, StageNameAnalysis,
+	StageNameQA, StageNameScene, StageNameStudio,
+}
+
+// tierStages is each tier's work as a stage list, outermost service first
+var tierStages = map[Tier][]string{
+	TierEnhanced:   {StageNameAnalysis, StageNameImage},
+	TierFinal:      {StageNameAnalysis, StageNameReview, StageNameQA, StageNameImage},
+	TierEnterprise: {StageNameExpression, StageNameArtStyle, StageNameAnalysis, StageNameReview, StageNameQA, StageNameImage},
+	Tier3D: {StageNamePose, StageNameScene, StageNameStudio, StageNameExpression, StageNameArtStyle,
+		StageNameAnalysis, StageNameReview, StageNameQA, StageNameImage},
+	TierMaster: {StageNameMaster, StageNamePose, StageNameScene, StageNameStudio, StageNameExpression,
+		StageNameArtStyle, StageNameAnalysis, StageNameReview, StageNameQA, StageNameImage},
+}
+
+// TierStages returns the stages a tier runs, as a starting point for a
+// custom list
+func TierStages(tier Tier) []string {
+	return append([]string(nil), tierStages[tier]...)
+}
+
+// Stage is one reusable step of a generation. Stages share a PipelineState:
+// most rewrite its prompt, some attach an analysis, and an error stops the
+// pipeline.
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, state *PipelineState) error
+}
+
+// PipelineState is the generation a pipeline's stages work on
+type PipelineState struct {
+	Prompt   string
+	Options  domain.GenerationOptions
+	Style    string
+	Filter   string
+	ShotType string
+	Mood     string
+	UserID   string
+
+	Cultural *ai.CulturalAnalysis
+	Master   *ai.MasterAnalysis
+	// Approved is the enriched prompt the final review passed; while it
+	// is still the prompt, the image stage draws it as written
+	Approved string
+	// Response collects the review, quality check, analysis and image in
+	// the shape every tier above enhanced returns
+	Response domain.FinalGenerationResponse
+}
+
+// PipelineResponse is the response of a generation run through a stage
+// list instead of a tier service
+type PipelineResponse struct {
+	domain.FinalGenerationResponse
+	Stages          []string             `json:"stages"`
+	CulturalContext *ai.CulturalAnalysis `json:"cultural_context,omitempty"`
+	MasterAnalysis  *ai.MasterAnalysis   `json:"master_analysis,omitempty"`
+}
+
+// Pipeline runs its stages in order
+type Pipeline struct {
+	stages []Stage
+}
+
+// Stages names the pipeline's stages in the order they run
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name()
+	}
+	return names
+}
+
+// Run stops at the first stage that fails or when ctx is cancelled
+func (p *Pipeline) Run(ctx context.Context, state *PipelineState) error {
+	for _, stage := range p.stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := stage.Run(ctx, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PipelineBuilder assembles a pipeline from the graph's builtin stages and
+// any custom ones. The first error is kept and returned by Build.
+type PipelineBuilder struct {
+	builtin map[string]Stage
+	stages  []Stage
+	err     error
+}
+
+// NewPipeline starts an empty stage list backed by the graph's services
+func (g *ServiceGraph) NewPipeline() *PipelineBuilder {
+	return &PipelineBuilder{builtin: g.stages}
+}
+
+// Add appends builtin stages by name
+func (b *PipelineBuilder) Add(names ...string) *PipelineBuilder {
+	for _, name := range names {
+		stage, ok := b.builtin[name]
+		if !ok {
+			b.fail(fmt.Errorf("unknown stage %q", name))
+			continue
+		}
+		b.stages = append(b.stages, stage)
+	}
+	return b
+}
+
+// Tier appends every stage of tier
+func (b *PipelineBuilder) Tier(tier Tier) *PipelineBuilder {
+	stages, ok := tierStages[tier]
+	if !ok {
+		b.fail(fmt.Errorf("unknown tier %q", tier))
+		return b
+	}
+	return b.Add(stages...)
+}
+
+// Use appends a custom stage
+func (b *PipelineBuilder) Use(stage Stage) *PipelineBuilder {
+	b.stages = append(b.stages, stage)
+	return b
+}
+
+// Without drops every stage with one of names, wherever it is in the list
+func (b *PipelineBuilder) Without(names ...string) *PipelineBuilder {
+	drop := make(map[string]bool, len(names))
+	for _, name := range names {
+		drop[name] = true
+	}
+
+	kept := b.stages[:0]
+	for _, stage := range b.stages {
+		if !drop[stage.Name()] {
+			kept = append(kept, stage)
+		}
+	}
+	b.stages = kept
+	return b
+}
+
+// Build checks the list is runnable and, unless it is exactly a tier's
+// list, that it runs the safety and quality gates before drawing
+func (b *PipelineBuilder) Build() (*Pipeline, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	pipeline := &Pipeline{stages: append([]Stage(nil), b.stages...)}
+	names := pipeline.Stages()
+	if err := checkOrder(names); err != nil {
+		return nil, err
+	}
+	if !isTierList(names) {
+		if err := checkGates(names); err != nil {
+			return nil, err
+		}
+	}
+	return pipeline, nil
+}
+
+// isTierList reports whether names is one tier's builtin stage list
+func isTierList(names []string) bool {
+	for _, stages := range tierStages {
+		if slices.Equal(stages, names) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *PipelineBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// ValidateStages checks a stage list from a request, a workspace or a
+// profile before it is stored or run
+func ValidateStages(names []string) error {
+	known := make(map[string]bool, len(StageNames))
+	for _, name := range StageNames {
+		known[name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("unknown stage %q (expected one of %v)", name, StageNames)
+		}
+	}
+	if err := checkOrder(names); err != nil {
+		return err
+	}
+	return checkGates(names)
+}
+
+// checkOrder requires exactly one image stage, last, so no stage rewrites
+// a prompt that has already been drawn
+func checkOrder(names []string) error {
+	if len(names) == 0 || names[len(names)-1] != StageNameImage {
+		return fmt.Errorf("the last stage must be %q", StageNameImage)
+	}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("stage %q appears more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// checkGates requires a custom stage list to run the final review, which
+// rejects unsafe prompts, and then quality assurance before it draws. Only
+// the builtin tier lists may leave them out.
+func checkGates(names []string) error {
+	position := make(map[string]int, len(names))
+	for i, name := range names {
+		position[name] = i
+	}
+
+	review, reviewed := position[StageNameReview]
+	qa, checked := position[StageNameQA]
+	if !reviewed || !checked || review > qa {
+		return fmt.Errorf("custom stages must include %q and then %q before %q", StageNameReview, StageNameQA, StageNameImage)
+	}
+	return nil
+}
+
+// requestPipeline is the profile or stage list req asks for. A profile
+// also fills in the request fields it has defaults for.
+func (g *ServiceGraph) requestPipeline(req *TierRequest) (*Pipeline, error) {
+	if req.Profile == "" {
+		if err := ValidateStages(req.Stages); err != nil {
+			return nil, err
+		}
+		return g.NewPipeline().Add(req.Stages...).Build()
+	}
+	if len(req.Stages) > 0 {
+		return nil, fmt.Errorf("set either stages or profile, not both")
+	}
+
+	profile, ok := g.profiles[req.Profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", req.Profile)
+	}
+	profile.Defaults.fill(req)
+	return profile.pipeline, nil
+}
+
+// runPipeline generates req through pipeline instead of its tier
+func (g *ServiceGraph) runPipeline(ctx context.Context, req TierRequest, options domain.GenerationOptions, pipeline *Pipeline) (*TierResult, error) {
+	state := &PipelineState{
+		Prompt:   req.Prompt,
+		Options:  options,
+		Style:    req.Style,
+		Filter:   req.Filter,
+		ShotType: req.ShotType,
+		Mood:     req.Mood,
+		UserID:   req.UserID,
+	}
+	if err := pipeline.Run(ctx, state); err != nil {
+		return nil, err
+	}
+
+	resp := &PipelineResponse{
+		FinalGenerationResponse: state.Response,
+		Stages:                  pipeline.Stages(),
+		CulturalContext:         state.Cultural,
+		MasterAnalysis:          state.Master,
+	}
+	return finalTierResult(req.Tier, &resp.FinalGenerationResponse, resp), nil
+}
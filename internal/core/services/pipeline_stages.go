@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// stageFunc adapts a function to Stage
+type stageFunc struct {
+	name string
+	run  func(ctx context.Context, state *PipelineState) error
+}
+
+func (s stageFunc) Name() string { return s.name }
+
+func (s stageFunc) Run(ctx context.Context, state *PipelineState) error {
+	return s.run(ctx, state)
+}
+
+// rewrite replaces the prompt and traces the change under the stage name
+func rewrite(ctx context.Context, state *PipelineState, stage string, agent string, prompt string) {
+	recordStep(ctx, stage, agent, state.Prompt, prompt)
+	state.Prompt = prompt
+}
+
+// builtinStages wraps the engines of the graph's tier services, so a stage
+// list shares them with the tiers instead of building its own. The master
+// agent and art style engine are passed in because a profile may tune them.
+func (g *ServiceGraph) builtinStages(masterAgent *ai.MasterPriorityAgent, artStyle *ai.ArtStyleEngine) map[string]Stage {
+	enhanced, final, enterprise3D := g.Enhanced, g.Final, g.Enterprise3D
+	expression := g.Enterprise.expressionEngine
+	cultural := ai.NewCulturalIntelligenceEngine()
+
+	stages := []Stage{
+		stageFunc{StageNameMaster, func(ctx context.Context, state *PipelineState) error {
+			analysis := masterAgent.AnalyzeAndPrioritizeTraced(state.Prompt, promptTracer(ctx))
+			reportProgress(ctx, StageMasterAnalysis, "Master analysis complete", map[string]interface{}{
+				"primary_style": analysis.ArtStyle.PrimaryStyle,
+				"confidence":    analysis.FinalPriorities.Confidence,
+				"passed":        analysis.QualityCheck.Passed,
+			})
+			if !analysis.QualityCheck.Passed {
+				return fmt.Errorf("master quality check failed: %v", analysis.QualityCheck.Issues)
+			}
+
+			state.Master = analysis
+			state.Prompt = analysis.OptimizedPrompt
+			// A requested style outranks the one the analysis picked
+			if state.Style == "" {
+				state.Style = analysis.ArtStyle.PrimaryStyle
+			}
+			return nil
+		}},
+
+		stageFunc{StageNameCultural, func(ctx context.Context, state *PipelineState) error {
+			state.Cultural = cultural.AnalyzeCulturalContext(state.Prompt)
+			rewrite(ctx, state, StageNameCultural, "cultural_engine", cultural.ApplyCulturalContext(state.Prompt, state.Cultural))
+			reportProgress(ctx, StageCulturalAnalysis, "Cultural analysis complete", map[string]interface{}{
+				"primary_culture": state.Cultural.PrimaryCulture,
+			})
+			return nil
+		}},
+
+		stageFunc{StageNamePose, func(ctx context.Context, state *PipelineState) error {
+			rewrite(ctx, state, StageNamePose, "anatomy_engine", enterprise3D.anatomyEngine.EnhancePoseDescription(state.Prompt))
+			reportProgress(ctx, StagePoseAnalysis, "3D pose analysis complete", nil)
+			return nil
+		}},
+
+		stageFunc{StageNameScene, func(ctx context.Context, state *PipelineState) error {
+			rewrite(ctx, state, StageNameScene, "scene_matcher", enterprise3D.sceneMatcher.EnhanceSceneDescription(state.Prompt))
+			reportProgress(ctx, StageSceneMatch, "Scene background matched", nil)
+			return nil
+		}},
+
+		stageFunc{StageNameStudio, func(ctx context.Context, state *PipelineState) error {
+			setup := enterprise3D.studioKnowledge.GetProfessionalStudioSetup(state.ShotType, state.Mood)
+			rewrite(ctx, state, StageNameStudio, "studio_knowledge", enterprise3D.applyStudioSetup(state.Prompt, setup))
+			reportProgress(ctx, StageStudioSetup, "Studio setup applied", map[string]interface{}{
+				"shot_type": state.ShotType,
+				"mood":      state.Mood,
+			})
+			return nil
+		}},
+
+		stageFunc{StageNameExpression, func(ctx context.Context, state *PipelineState) error {
+			rewrite(ctx, state, StageNameExpression, "expression_engine", expression.EnhanceCharacterDescription(state.Prompt))
+			return nil
+		}},
+
+		stageFunc{StageNameArtStyle, func(ctx context.Context, state *PipelineState) error {
+			rewrite(ctx, state, StageNameArtStyle, "art_style_engine", artStyle.ApplyArtStyle(state.Prompt, state.Style))
+			if state.Filter != "" {
+				rewrite(ctx, state, StageNameArtStyle, "art_style_filter", artStyle.ApplyFilter(state.Prompt, state.Filter))
+			}
+			reportProgress(ctx, StageStyleApplied, "Art style applied", map[string]interface{}{
+				"style":  state.Style,
+				"filter": state.Filter,
+			})
+			return nil
+		}},
+
+		stageFunc{StageNameAnalysis, func(ctx context.Context, state *PipelineState) error {
+			state.Response.GenerationResponse.Analysis = enhanced.analyze(ctx, domain.GenerationRequest{
+				UserPrompt: state.Prompt,
+				Options:    state.Options,
+				UserID:     state.UserID,
+			})
+			return nil
+		}},
+
+		stageFunc{StageNameReview, func(ctx context.Context, state *PipelineState) error {
+			// Review what the image stage would draw, as the final tier does
+			finalPrompt, err := final.finalReview.ReviewAndFinalizePromptTraced(
+				enhanced.enrich(ctx, domain.GenerationRequest{
+					UserPrompt: state.Prompt,
+					Options:    state.Options,
+					UserID:     state.UserID,
+				}),
+				ai.GenerationContext{
+					Style:     state.Style,
+					Intent:    final.detectIntent(state.Prompt),
+					UserLevel: final.assessUserLevel(ctx, state.UserID),
+				},
+				promptTracer(ctx),
+			)
+			if err != nil {
+				return fmt.Errorf("final review failed: %v", err)
+			}
+			reportProgress(ctx, StageFinalReview, "Final review complete", map[string]interface{}{
+				"approved":   finalPrompt.Review.Approved,
+				"confidence": finalPrompt.Confidence,
+			})
+			if !finalPrompt.Review.Approved {
+				return domain.NewAppError(nil, fmt.Sprintf("prompt not approved: %v", finalPrompt.Review.GetIssues()), domain.ErrCodeSafetyRejected)
+			}
+
+			state.Prompt = finalPrompt.Final
+			state.Approved = finalPrompt.Final
+			state.Response.Review = finalPrompt.Review
+			state.Response.Confidence = finalPrompt.Confidence
+			return nil
+		}},
+
+		stageFunc{StageNameQA, func(ctx context.Context, state *PipelineState) error {
+			qualityCheck := final.qualityAssurance.VerifyQuality(state.Prompt)
+			reportProgress(ctx, StageQualityAssurance, "Quality assurance complete", map[string]interface{}{
+				"passed": qualityCheck.Passed,
+				"issues": qualityCheck.Issues,
+			})
+			if !qualityCheck.Passed {
+				return domain.NewAppError(nil, fmt.Sprintf("quality assurance failed: %v", qualityCheck.Issues), domain.ErrCodeQAFailed)
+			}
+
+			state.Response.QualityCheck = qualityCheck
+			return nil
+		}},
+
+		stageFunc{StageNameImage, func(ctx context.Context, state *PipelineState) error {
+			draw := enhanced.draw
+			if state.Approved != "" && state.Approved == state.Prompt {
+				draw = enhanced.drawApproved
+			}
+			response, err := draw(ctx, domain.GenerationRequest{
+				UserPrompt: state.Prompt,
+				Options:    state.Options,
+				UserID:     state.UserID,
+			})
+			if err != nil {
+				return err
+			}
+			recordStep(ctx, StageNameImage, "image_generator", state.Prompt, response.EnrichedPrompt)
+
+			state.Response.FinalPrompt = state.Prompt
+			state.Response.GenerationResponse.GenerationResponse = *response
+			return nil
+		}},
+	}
+
+	byName := make(map[string]Stage, len(stages))
+	for _, stage := range stages {
+		byName[stage.Name()] = stage
+	}
+	return byName
+}
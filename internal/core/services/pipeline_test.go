@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+// testStages is every builtin stage name, each doing nothing
+func testStages() map[string]Stage {
+	stages := make(map[string]Stage, len(StageNames))
+	for _, name := range StageNames {
+		stages[name] = stageFunc{name, func(context.Context, *PipelineState) error { return nil }}
+	}
+	return stages
+}
+
+func TestBuildRequiresGatesOnCustomLists(t *testing.T) {
+	cases := []struct {
+		name   string
+		stages []string
+		ok     bool
+	}{
+		{"enhanced tier", tierStages[TierEnhanced], true},
+		{"master tier", tierStages[TierMaster], true},
+		{"gated custom list", []string{StageNameCultural, StageNameReview, StageNameQA, StageNameImage}, true},
+		{"no review", []string{StageNameCultural, StageNameAnalysis, StageNameImage}, false},
+		{"qa before review", []string{StageNameQA, StageNameReview, StageNameImage}, false},
+		{"image not last", []string{StageNameReview, StageNameQA, StageNameImage, StageNameCultural}, false},
+	}
+	for _, c := range cases {
+		_, err := (&PipelineBuilder{builtin: testStages()}).Add(c.stages...).Build()
+		if (err == nil) != c.ok {
+			t.Errorf("%s: err = %v, want ok %v", c.name, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// HistoryRepository persists completed generations
+type HistoryRepository interface {
+	SaveGeneration(ctx context.Context, record *domain.GenerationRecord) error
+	GetUserHistory(ctx context.Context, userID string, limit int) ([]*domain.GenerationRecord, error)
+}
+
+// Logger is the minimal structured logger the services write to
+type Logger interface {
+	Info(msg string, fields ...interface{})
+	Error(msg string, err error, fields ...interface{})
+}
+
+// ImageProvider is an image backend. Name identifies it in requests, so it
+// must be unique among the graph's providers.
+type ImageProvider interface {
+	Name() string
+	Capabilities() domain.ProviderCapabilities
+	Generate(ctx context.Context, req domain.ImageRequest) (*domain.Image, error)
+}
+
+// ProviderObserver is told the outcome of every image provider call. A
+// refused request is reported as a success: the provider answered.
+type ProviderObserver interface {
+	ObserveProvider(name string, latency time.Duration, err error)
+}
+
+// TenantDirectory looks up the workspace a caller's identity belongs to
+type TenantDirectory interface {
+	Get(id string) (*domain.Tenant, bool)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"geminizer-enterprise/internal/core/ai"
+)
+
+// Tuning overrides agent thresholds and defaults. A zero field keeps the
+// value it overrides: the agent's builtin one for the graph, the graph's
+// for a profile.
+type Tuning struct {
+	// StyleThreshold is the confidence the master agent needs to detect a
+	// style (builtin 0.3)
+	StyleThreshold float64 `json:"style_threshold,omitempty"`
+	// DefaultStyle is drawn when no style, or an unknown one, is
+	// requested (builtin unreal_engine_5)
+	DefaultStyle string `json:"default_style,omitempty"`
+}
+
+// over fills t's zero fields from base
+func (t Tuning) over(base Tuning) Tuning {
+	if t.StyleThreshold == 0 {
+		t.StyleThreshold = base.StyleThreshold
+	}
+	if t.DefaultStyle == "" {
+		t.DefaultStyle = base.DefaultStyle
+	}
+	return t
+}
+
+func (t Tuning) apply(masterAgent *ai.MasterPriorityAgent, artStyle *ai.ArtStyleEngine) error {
+	if t.StyleThreshold != 0 {
+		if t.StyleThreshold < 0 || t.StyleThreshold > 1 {
+			return fmt.Errorf("style_threshold must be between 0 and 1, got %v", t.StyleThreshold)
+		}
+		masterAgent.SetStyleThreshold(t.StyleThreshold)
+	}
+	if t.DefaultStyle != "" {
+		if err := artStyle.SetDefaultStyle(t.DefaultStyle); err != nil {
+			return fmt.Errorf("default_style: %v", err)
+		}
+	}
+	return nil
+}
+
+// Profile is a named pipeline a request can select: the stages it runs, in
+// order, how their agents are tuned and the request fields it defaults
+type Profile struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Stages      []string        `json:"stages"`
+	Tuning      Tuning          `json:"tuning"`
+	Defaults    ProfileDefaults `json:"defaults"`
+}
+
+// ProfileDefaults fill the fields a request leaves empty
+type ProfileDefaults struct {
+	Style    string `json:"style,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+	ShotType string `json:"shot_type,omitempty"`
+	Mood     string `json:"mood,omitempty"`
+}
+
+func (d ProfileDefaults) fill(req *TierRequest) {
+	if req.Style == "" {
+		req.Style = d.Style
+	}
+	if req.Filter == "" {
+		req.Filter = d.Filter
+	}
+	if req.ShotType == "" {
+		req.ShotType = d.ShotType
+	}
+	if req.Mood == "" {
+		req.Mood = d.Mood
+	}
+}
+
+type profile struct {
+	Profile
+	pipeline *Pipeline
+}
+
+// Tune applies to every tier and stage list; call it before SetProfiles so
+// the profiles inherit it
+func (g *ServiceGraph) Tune(tuning Tuning) error {
+	if err := tuning.apply(g.Master.masterAgent, g.Enterprise.artStyleEngine); err != nil {
+		return err
+	}
+	g.tuning = tuning
+	return nil
+}
+
+// SetProfiles replaces the selectable profiles. A profile that tunes an
+// agent gets its own instance of it, so it never affects other requests.
+func (g *ServiceGraph) SetProfiles(profiles []Profile) error {
+	built := make(map[string]*profile, len(profiles))
+	for _, p := range profiles {
+		if _, taken := built[p.Name]; taken {
+			return fmt.Errorf("profile %q: defined more than once", p.Name)
+		}
+
+		stages := g.stages
+		if p.Tuning != (Tuning{}) {
+			masterAgent, artStyle := ai.NewMasterPriorityAgent(), ai.NewArtStyleEngine()
+			if err := p.Tuning.over(g.tuning).apply(masterAgent, artStyle); err != nil {
+				return fmt.Errorf("profile %q: %v", p.Name, err)
+			}
+			stages = g.builtinStages(masterAgent, artStyle)
+		}
+
+		pipeline, err := (&PipelineBuilder{builtin: stages}).Add(p.Stages...).Build()
+		if err != nil {
+			return fmt.Errorf("profile %q: %v", p.Name, err)
+		}
+		built[p.Name] = &profile{Profile: p, pipeline: pipeline}
+	}
+
+	g.profiles = built
+	return nil
+}
+
+// Profiles lists the selectable profiles by name
+func (g *ServiceGraph) Profiles() []Profile {
+	profiles := make([]Profile, 0, len(g.profiles))
+	for _, p := range g.profiles {
+		profiles = append(profiles, p.Profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
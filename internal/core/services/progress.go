@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// Progress stages, in the order a master-tier generation emits them
+const (
+	StageMasterAnalysis   = "master_analysis"
+	StagePoseAnalysis     = "pose_analysis"
+	StageSceneMatch       = "scene_match"
+	StageStudioSetup      = "studio_setup"
+	StageStyleApplied     = "style_applied"
+	StagePromptAnalysis   = "prompt_analysis"
+	StageFinalReview      = "final_review"
+	StageQualityAssurance = "quality_assurance"
+	StageImageReady       = "image_ready"
+
+	// Only emitted by stage lists that include cultural analysis
+	StageCulturalAnalysis = "cultural_analysis"
+)
+
+// ProgressFunc receives stage events. It is called synchronously from the
+// generation goroutine, so it must not block for long.
+type ProgressFunc func(event domain.ProgressEvent)
+
+type progressKey struct{}
+
+// WithProgress reports each finished stage of generations run with ctx
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, stage string, message string, data interface{}) {
+	fn, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	if !ok || fn == nil {
+		return
+	}
+
+	fn(domain.ProgressEvent{
+		Stage:     stage,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+	})
+}
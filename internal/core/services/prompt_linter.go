@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"geminizer-enterprise/internal/core/ai"
+)
+
+// Severity ranks lint findings; higher is worse
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity accepts info, warning or error
+func ParseSeverity(name string) (Severity, error) {
+	switch name {
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return 0, fmt.Errorf("unknown severity %q (expected info, warning or error)", name)
+}
+
+// LintFinding is one problem found in a prompt
+type LintFinding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// PromptLinter checks prompts with the validation, safety and quality
+// agents. It never calls the image backend.
+type PromptLinter struct {
+	master           *MasterGenerationService
+	nluEngine        *ai.NLUEngine
+	qualityAgent     *ai.QualityAssessmentAgent
+	safetyAnalyzer   *ai.AdvancedSafetyAnalyzer
+	explicitDetector *ai.ExplicitDetector
+	minQuality       float64
+}
+
+func NewPromptLinter(master *MasterGenerationService) *PromptLinter {
+	return &PromptLinter{
+		master:           master,
+		nluEngine:        ai.NewNLUEngine(),
+		qualityAgent:     ai.NewQualityAssessmentAgent(),
+		safetyAnalyzer:   ai.NewAdvancedSafetyAnalyzer(),
+		explicitDetector: ai.NewExplicitDetector(),
+		minQuality:       0.5,
+	}
+}
+
+// Lint runs every check and returns findings, most severe first
+func (l *PromptLinter) Lint(prompt string) []LintFinding {
+	var findings []LintFinding
+
+	// Check 1: Hard safety failures
+	if l.explicitDetector.ContainsExplicitContent(prompt) {
+		findings = append(findings, LintFinding{"safety/explicit", SeverityError, "Prompt contains explicit or banned content"})
+	}
+	if l.explicitDetector.ContainsAgeInappropriate(prompt) {
+		findings = append(findings, LintFinding{"safety/age", SeverityError, "Prompt contains age-inappropriate content"})
+	}
+	for _, issue := range l.safetyAnalyzer.AnalyzeAdvancedSafety(prompt).Issues {
+		findings = append(findings, LintFinding{"safety/context", SeverityError, issue})
+	}
+
+	// Check 2: Master validation (conflicts and quality enforcement)
+	validation := l.master.ValidatePrompt(prompt)
+	if !validation.IsValid {
+		findings = append(findings, LintFinding{"validation/quality-check", SeverityError, "Master quality check would reject this prompt"})
+	}
+	for _, conflict := range validation.Conflicts {
+		findings = append(findings, LintFinding{"validation/conflict", SeverityWarning, fmt.Sprintf("%v", conflict)})
+	}
+	if validation.Confidence < 0.5 {
+		findings = append(findings, LintFinding{"validation/confidence", SeverityWarning,
+			fmt.Sprintf("Low prioritization confidence (%.2f)", validation.Confidence)})
+	}
+	for _, suggestion := range validation.Suggestions {
+		findings = append(findings, LintFinding{"validation/suggestion", SeverityInfo, fmt.Sprintf("%v", suggestion)})
+	}
+
+	// Check 3: Prompt quality
+	understanding := l.nluEngine.UnderstandPrompt(prompt)
+	quality := l.qualityAgent.AssessPromptQuality(prompt, understanding.Intent)
+	if quality.Score < l.minQuality {
+		findings = append(findings, LintFinding{"quality/score", SeverityWarning,
+			fmt.Sprintf("Quality score %.2f is below %.2f (%s)", quality.Score, l.minQuality, quality.ProfessionalLevel)})
+	}
+	for _, weakness := range quality.Weaknesses {
+		findings = append(findings, LintFinding{"quality/weakness", SeverityInfo, weakness})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	return findings
+}
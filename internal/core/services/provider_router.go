@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// AutoProvider asks for whichever healthy provider supports the request
+const AutoProvider = "auto"
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// maxRetryTokens caps the retry budget, so a long quiet spell does not
+// save up a burst of retries for the next outage
+const maxRetryTokens = 10
+
+// healthSmoothing is the weight of the newest call in a provider's
+// moving averages
+const healthSmoothing = 0.2
+
+// RoutingConfig bounds how hard one image tries the providers
+type RoutingConfig struct {
+	Timeout          time.Duration // per attempt
+	MaxAttempts      int           // attempts per image, across providers
+	FailureThreshold int           // consecutive failures that open a breaker
+	OpenDuration     time.Duration // how long an open breaker turns calls away
+	RetryBudget      float64       // retries earned per image drawn
+}
+
+func DefaultRoutingConfig() RoutingConfig {
+	return RoutingConfig{
+		Timeout:          time.Minute,
+		MaxAttempts:      3,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+		RetryBudget:      0.2,
+	}
+}
+
+// providerRoute is one provider with its circuit breaker and recent health
+type providerRoute struct {
+	provider ImageProvider
+
+	mu       sync.Mutex
+	state    string
+	failures int // consecutive
+	openedAt time.Time
+	trial    bool    // a half-open trial call is in flight
+	success  float64 // moving average, 1 when every call succeeds
+	latency  time.Duration
+}
+
+func newProviderRoute(provider ImageProvider) *providerRoute {
+	return &providerRoute{provider: provider, state: BreakerClosed, success: 1}
+}
+
+// allow reports whether a call may go through. An open breaker turns
+// calls away until its cool-down has passed, then lets one trial through.
+func (r *providerRoute) allow(now time.Time, openFor time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case BreakerOpen:
+		if now.Sub(r.openedAt) < openFor {
+			return false
+		}
+		r.state = BreakerHalfOpen
+		r.trial = true
+		return true
+	case BreakerHalfOpen:
+		if r.trial {
+			return false
+		}
+		r.trial = true
+		return true
+	}
+	return true
+}
+
+// record updates the breaker and moving averages with a call's outcome
+func (r *providerRoute) record(err error, latency time.Duration, threshold int, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	outcome := 1.0
+	if err != nil {
+		outcome = 0
+	}
+	r.success += healthSmoothing * (outcome - r.success)
+	r.latency += time.Duration(healthSmoothing * float64(latency-r.latency))
+	r.trial = false
+
+	if err == nil {
+		r.state, r.failures = BreakerClosed, 0
+		return
+	}
+	r.failures++
+	if r.state == BreakerHalfOpen || r.failures >= threshold {
+		r.state, r.openedAt = BreakerOpen, now
+	}
+}
+
+// release gives back a trial that allow granted but no call used
+func (r *providerRoute) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == BreakerHalfOpen && r.trial {
+		r.state, r.trial = BreakerOpen, false
+	}
+}
+
+// weight is the route's share of traffic: its success rate, discounted by
+// up to half as its latency approaches the attempt timeout
+func (r *providerRoute) weight(timeout time.Duration) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == BreakerOpen {
+		return 0
+	}
+	slowness := float64(r.latency) / float64(timeout)
+	return max(r.success*(1-0.5*min(slowness, 1)), 0.01)
+}
+
+func (r *providerRoute) status() (string, float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state, r.success
+}
+
+// providerRouter draws each image with the first provider that can, in
+// order of preference and health
+type providerRouter struct {
+	config   RoutingConfig
+	routes   map[string]*providerRoute
+	observer ProviderObserver
+
+	mu     sync.Mutex
+	tokens float64 // retry budget
+}
+
+func newProviderRouter(providers map[string]ImageProvider, config RoutingConfig, observer ProviderObserver) *providerRouter {
+	routes := make(map[string]*providerRoute, len(providers))
+	for name, provider := range providers {
+		routes[name] = newProviderRoute(provider)
+	}
+	return &providerRouter{config: config, routes: routes, observer: observer, tokens: maxRetryTokens}
+}
+
+// generate tries the selection's providers until one draws the image, a
+// provider refuses the request or the attempts or retry budget run out.
+// It returns the prompt the drawing provider was sent.
+func (r *providerRouter) generate(ctx context.Context, selection *imageSelection, prompt string) (*domain.Image, string, error) {
+	r.deposit()
+
+	queue := r.order(selection)
+	var failures []string
+	for attempts := 0; attempts < r.config.MaxAttempts && len(queue) > 0; {
+		route := queue[0]
+		queue = queue[1:]
+		name := route.provider.Name()
+
+		if !route.allow(time.Now(), r.config.OpenDuration) {
+			failures = append(failures, name+": circuit open")
+			continue
+		}
+		if attempts > 0 && !r.spendRetry() {
+			route.release()
+			failures = append(failures, "retry budget exhausted")
+			break
+		}
+		attempts++
+
+		image, sent, err := r.call(ctx, route, selection, prompt)
+		if err == nil {
+			return image, sent, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		if !retryable(err) {
+			return nil, "", fmt.Errorf("image provider %s: %v", name, err)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+
+		// Retry the same provider only after the others have had a turn
+		queue = append(queue, route)
+	}
+
+	if len(failures) == 0 {
+		failures = append(failures, "no provider available")
+	}
+	message := "no image provider could draw the image: " + strings.Join(failures, "; ")
+	return nil, "", domain.NewAppError(nil, message, domain.ErrCodeUnavailable)
+}
+
+// call runs one attempt under the attempt timeout and records its outcome.
+// A refused request still shows the provider is up, and a caller that gave
+// up says nothing about the provider either way.
+func (r *providerRouter) call(ctx context.Context, route *providerRoute, selection *imageSelection, prompt string) (*domain.Image, string, error) {
+	provider := route.provider
+	sent := fitPrompt(prompt, provider.Capabilities().MaxPromptLength)
+
+	attemptCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	image, err := provider.Generate(attemptCtx, domain.ImageRequest{
+		Prompt:         sent,
+		NegativePrompt: selection.negativePrompt,
+		AspectRatio:    selection.aspectRatio,
+	})
+	latency := time.Since(start)
+	if err != nil && ctx.Err() == nil && attemptCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", r.config.Timeout)
+	}
+
+	if ctx.Err() != nil {
+		route.release()
+		return nil, "", ctx.Err()
+	}
+	outcome := err
+	if !retryable(err) {
+		outcome = nil
+	}
+	route.record(outcome, latency, r.config.FailureThreshold, time.Now())
+	if r.observer != nil {
+		r.observer.ObserveProvider(provider.Name(), latency, outcome)
+	}
+	return image, sent, err
+}
+
+// order lists the providers to try: the selected one first, then, when
+// the selection may fail over, the others that support the request in a
+// health-weighted random order
+func (r *providerRouter) order(selection *imageSelection) []*providerRoute {
+	preferred := r.routes[selection.preferred]
+	if !selection.failover {
+		return []*providerRoute{preferred}
+	}
+
+	names := make([]string, 0, len(r.routes))
+	for name := range r.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pool []*providerRoute
+	for _, name := range names {
+		if route := r.routes[name]; route != preferred && selection.supports(route.provider.Capabilities()) {
+			pool = append(pool, route)
+		}
+	}
+
+	var ordered []*providerRoute
+	if preferred != nil && selection.supports(preferred.provider.Capabilities()) {
+		ordered = append(ordered, preferred)
+	}
+	for len(pool) > 0 {
+		i := r.pick(pool)
+		ordered = append(ordered, pool[i])
+		pool = append(pool[:i], pool[i+1:]...)
+	}
+	return ordered
+}
+
+// pick draws an index with probability proportional to the route's weight
+func (r *providerRouter) pick(pool []*providerRoute) int {
+	weights := make([]float64, len(pool))
+	total := 0.0
+	for i, route := range pool {
+		weights[i] = route.weight(r.config.Timeout)
+		total += weights[i]
+	}
+	if total == 0 {
+		return 0
+	}
+
+	draw := rand.Float64() * total
+	for i, weight := range weights {
+		if draw < weight {
+			return i
+		}
+		draw -= weight
+	}
+	return len(pool) - 1
+}
+
+// deposit earns the retry budget of one image
+func (r *providerRouter) deposit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = min(r.tokens+r.config.RetryBudget, maxRetryTokens)
+}
+
+// spendRetry takes one retry from the budget, so retries stay a bounded
+// share of traffic during an outage
+func (r *providerRouter) spendRetry() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// retryable reports whether another attempt could succeed. A provider
+// marks a request its backend refused with a Retryable method.
+func retryable(err error) bool {
+	var marked interface{ Retryable() bool }
+	if errors.As(err, &marked) {
+		return marked.Retryable()
+	}
+	return true
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// testBackend is an image server answering every request with status
+type testBackend struct {
+	*httptest.Server
+	status atomic.Int32
+	calls  atomic.Int32
+}
+
+func newTestBackend(t *testing.T, status int) *testBackend {
+	backend := &testBackend{}
+	backend.status.Store(int32(status))
+	backend.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backend.calls.Add(1)
+		if code := int(backend.status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"url": backend.URL + "/image.png"})
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+// statusError mirrors the adapters' errors: only timeouts, rate limits and
+// server errors are worth another attempt
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("status %d", int(e)) }
+
+func (e statusError) Retryable() bool {
+	return e == http.StatusRequestTimeout || e == http.StatusTooManyRequests || e >= 500
+}
+
+// httpProvider draws with a testBackend
+type httpProvider struct {
+	name    string
+	backend *testBackend
+}
+
+func (p *httpProvider) Name() string { return p.name }
+
+func (p *httpProvider) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{NegativePrompt: true, AspectRatios: []string{"1:1"}}
+}
+
+func (p *httpProvider) Generate(ctx context.Context, req domain.ImageRequest) (*domain.Image, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.backend.URL, strings.NewReader(req.Prompt))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.backend.Client().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return &domain.Image{URL: body.URL}, nil
+}
+
+func testRouter(config RoutingConfig, backends map[string]*testBackend) *providerRouter {
+	providers := make(map[string]ImageProvider, len(backends))
+	for name, backend := range backends {
+		providers[name] = &httpProvider{name: name, backend: backend}
+	}
+	return newProviderRouter(providers, config, nil)
+}
+
+func TestRouterFailsOverToAnotherProvider(t *testing.T) {
+	down := newTestBackend(t, http.StatusServiceUnavailable)
+	up := newTestBackend(t, http.StatusOK)
+	router := testRouter(DefaultRoutingConfig(), map[string]*testBackend{"down": down, "up": up})
+
+	image, _, err := router.generate(context.Background(), &imageSelection{preferred: "down", failover: true}, "a lighthouse")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if image.URL != up.URL+"/image.png" {
+		t.Errorf("image drawn by %q, want the healthy provider", image.URL)
+	}
+	if down.calls.Load() != 1 || up.calls.Load() != 1 {
+		t.Errorf("calls = down %d, up %d; want 1 each", down.calls.Load(), up.calls.Load())
+	}
+}
+
+func TestRouterDoesNotFailOverARefusal(t *testing.T) {
+	refusing := newTestBackend(t, http.StatusBadRequest)
+	up := newTestBackend(t, http.StatusOK)
+	router := testRouter(DefaultRoutingConfig(), map[string]*testBackend{"refusing": refusing, "up": up})
+
+	_, _, err := router.generate(context.Background(), &imageSelection{preferred: "refusing", failover: true}, "x")
+	if err == nil {
+		t.Fatal("generate succeeded after a refusal")
+	}
+	if up.calls.Load() != 0 {
+		t.Errorf("a refused request was sent to %d other providers", up.calls.Load())
+	}
+	if state, _ := router.routes["refusing"].status(); state != BreakerClosed {
+		t.Errorf("a refusal moved the breaker to %s", state)
+	}
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	backend := newTestBackend(t, http.StatusInternalServerError)
+	config := DefaultRoutingConfig()
+	config.MaxAttempts = 1
+	config.FailureThreshold = 2
+	config.OpenDuration = time.Hour
+	router := testRouter(config, map[string]*testBackend{"flaky": backend})
+	selection := &imageSelection{preferred: "flaky"}
+
+	for i := 0; i < 2; i++ {
+		router.generate(context.Background(), selection, "x")
+	}
+	if state, _ := router.routes["flaky"].status(); state != BreakerOpen {
+		t.Fatalf("breaker %s after %d failures, want open", state, config.FailureThreshold)
+	}
+
+	_, _, err := router.generate(context.Background(), selection, "x")
+	if domain.ErrorCode(err) != domain.ErrCodeUnavailable || !strings.Contains(err.Error(), "circuit open") {
+		t.Errorf("err = %v, want an unavailable circuit-open error", err)
+	}
+	if backend.calls.Load() != 2 {
+		t.Errorf("an open breaker let a call through: %d calls", backend.calls.Load())
+	}
+}
+
+func TestBreakerHalfOpenTrial(t *testing.T) {
+	backend := newTestBackend(t, http.StatusInternalServerError)
+	config := DefaultRoutingConfig()
+	config.MaxAttempts = 1
+	config.FailureThreshold = 1
+	config.OpenDuration = time.Hour
+	router := testRouter(config, map[string]*testBackend{"flaky": backend})
+	route := router.routes["flaky"]
+	selection := &imageSelection{preferred: "flaky"}
+
+	// cool sends the breaker past its open duration
+	cool := func() {
+		route.mu.Lock()
+		route.openedAt = time.Now().Add(-2 * config.OpenDuration)
+		route.mu.Unlock()
+	}
+
+	router.generate(context.Background(), selection, "x")
+	cool()
+
+	// Only one trial goes through while half open
+	if !route.allow(time.Now(), config.OpenDuration) {
+		t.Fatal("cooled breaker refused the trial")
+	}
+	if route.allow(time.Now(), config.OpenDuration) {
+		t.Error("half-open breaker allowed a second trial")
+	}
+	route.release()
+	if state, _ := route.status(); state != BreakerOpen {
+		t.Fatalf("released trial left the breaker %s, want open", state)
+	}
+
+	// A failed trial opens the breaker again
+	cool()
+	router.generate(context.Background(), selection, "x")
+	if state, _ := route.status(); state != BreakerOpen {
+		t.Fatalf("breaker %s after a failed trial, want open", state)
+	}
+
+	// A successful trial closes it
+	cool()
+	backend.status.Store(http.StatusOK)
+	if _, _, err := router.generate(context.Background(), selection, "x"); err != nil {
+		t.Fatalf("trial: %v", err)
+	}
+	if state, _ := route.status(); state != BreakerClosed {
+		t.Errorf("breaker %s after a successful trial, want closed", state)
+	}
+}
+
+func TestRetryBudgetBoundsRetries(t *testing.T) {
+	first := newTestBackend(t, http.StatusServiceUnavailable)
+	second := newTestBackend(t, http.StatusServiceUnavailable)
+	config := DefaultRoutingConfig()
+	config.RetryBudget = 0.5
+	router := testRouter(config, map[string]*testBackend{"first": first, "second": second})
+	router.tokens = 0
+	selection := &imageSelection{preferred: "first", failover: true}
+
+	// Half a retry earned: the first failure is not retried
+	_, _, err := router.generate(context.Background(), selection, "x")
+	if err == nil || !strings.Contains(err.Error(), "retry budget exhausted") {
+		t.Fatalf("err = %v, want the retry budget exhausted", err)
+	}
+	if first.calls.Load() != 1 || second.calls.Load() != 0 {
+		t.Fatalf("calls = first %d, second %d; want 1 and 0", first.calls.Load(), second.calls.Load())
+	}
+
+	// A whole retry earned: one retry, on the other provider, then stop
+	router.generate(context.Background(), selection, "x")
+	if first.calls.Load() != 2 || second.calls.Load() != 1 {
+		t.Errorf("calls = first %d, second %d; want 2 and 1", first.calls.Load(), second.calls.Load())
+	}
+}
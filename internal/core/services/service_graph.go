@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// Tier selects how much of the generation pipeline a request runs through
+type Tier string
+
+const (
+	TierEnhanced   Tier = "enhanced"
+	TierFinal      Tier = "final"
+	TierEnterprise Tier = "enterprise"
+	Tier3D         Tier = "3d"
+	TierMaster     Tier = "master"
+)
+
+// Tiers lists every tier from the lightest to the full master pipeline
+var Tiers = []Tier{TierEnhanced, TierFinal, TierEnterprise, Tier3D, TierMaster}
+
+// ParseTier validates a tier name coming from a flag or request body
+func ParseTier(name string) (Tier, error) {
+	for _, tier := range Tiers {
+		if string(tier) == name {
+			return tier, nil
+		}
+	}
+	return "", fmt.Errorf("unknown tier %q (expected one of %v)", name, Tiers)
+}
+
+// TierRequest carries the union of inputs accepted by every tier
+type TierRequest struct {
+	Tier     Tier                     `json:"tier" validate:"enum=tier"`
+	Prompt   string                   `json:"prompt" validate:"required,min=1,max=4000"`
+	Style    string                   `json:"style,omitempty" validate:"enum=style"`
+	Filter   string                   `json:"filter,omitempty" validate:"enum=filter"`
+	ShotType string                   `json:"shot_type,omitempty" validate:"enum=shot_type"`
+	Mood     string                   `json:"mood,omitempty" validate:"enum=mood"`
+	UserID   string                   `json:"user_id,omitempty"`
+	Options  domain.GenerationOptions `json:"options"`
+	Explain  bool                     `json:"explain,omitempty"`
+	// WebhookURL receives the outcome event instead of the tenant's endpoint
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// CustomStyle names one of the caller's workspace styles
+	CustomStyle string `json:"custom_style,omitempty" validate:"max=64"`
+	// Stages replaces the tier's stage list; the tier still names the
+	// generation in history and events
+	Stages []string `json:"stages,omitempty" validate:"max=20,enum=stage"`
+	// Profile runs a configured pipeline profile instead of the tier
+	Profile string `json:"profile,omitempty" validate:"max=64"`
+	// Provider names the image backend that draws, or "auto" for any
+	// healthy one; empty uses the default, which may fail over
+	Provider       string `json:"provider,omitempty" validate:"max=64"`
+	NegativePrompt string `json:"negative_prompt,omitempty" validate:"max=2000"`
+	AspectRatio    string `json:"aspect_ratio,omitempty" validate:"enum=aspect_ratio"`
+	// DryRun runs every analysis, review and check but draws no image
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// TierResult is the tier-independent view of a generation
+type TierResult struct {
+	Tier        Tier                       `json:"tier"`
+	FinalPrompt string                     `json:"final_prompt"`
+	ImageURL    string                     `json:"image_url"`
+	Analysis    *domain.GenerationAnalysis `json:"analysis,omitempty"`
+	Response    interface{}                `json:"response"`
+	Trace       *domain.PipelineTrace      `json:"trace,omitempty"`
+	DryRun      bool                       `json:"dry_run,omitempty"`
+}
+
+// ServiceGraph holds one instance of every generation tier. The tiers are
+// nested, so the graph is built from the master service down and shares
+// the inner services instead of constructing five separate chains.
+type ServiceGraph struct {
+	Enhanced     *EnhancedImageGenerator
+	Final        *FinalGenerationService
+	Enterprise   *EnterpriseGenerationService
+	Enterprise3D *Enterprise3DGenerationService
+	Master       *MasterGenerationService
+	Comics       *ComicService
+
+	repo       HistoryRepository
+	logger     Logger
+	notifier   Notifier
+	moderation *ai.ModerationHistory
+	tenants    TenantDirectory
+	stages     map[string]Stage
+	tuning     Tuning
+	profiles   map[string]*profile
+
+	router           *providerRouter
+	defaultProvider  string
+	providerObserver ProviderObserver
+}
+
+func NewServiceGraph(repo HistoryRepository, logger Logger) *ServiceGraph {
+	master := NewMasterGenerationService(repo, logger)
+	enterprise3D := master.enterprise3D
+	enterprise := enterprise3D.enterpriseGen
+	final := enterprise.finalGeneration
+
+	graph := &ServiceGraph{
+		Enhanced:     final.imageGenerator,
+		Final:        final,
+		Enterprise:   enterprise,
+		Enterprise3D: enterprise3D,
+		Master:       master,
+		Comics:       NewComicService(),
+		repo:         repo,
+		logger:       logger,
+	}
+	graph.stages = graph.builtinStages(master.masterAgent, enterprise.artStyleEngine)
+	return graph
+}
+
+// SetNotifier reports every finished generation and comic to notifier
+func (g *ServiceGraph) SetNotifier(notifier Notifier) {
+	g.notifier = notifier
+	g.Comics.notifier = notifier
+}
+
+// SetModerationHistory records the safety violations of every generation
+// and comic in history
+func (g *ServiceGraph) SetModerationHistory(history *ai.ModerationHistory) {
+	g.moderation = history
+	g.Comics.mu.Lock()
+	g.Comics.moderation = history
+	g.Comics.mu.Unlock()
+}
+
+// SetTenants applies each workspace's custom styles and safety policy to
+// the generations of its users
+func (g *ServiceGraph) SetTenants(tenants TenantDirectory) {
+	g.tenants = tenants
+}
+
+// Generate dispatches a request to the service behind its tier and records
+// the completed generation in history
+func (g *ServiceGraph) Generate(ctx context.Context, req TierRequest) (*TierResult, error) {
+	if identity, ok := IdentityFrom(ctx); ok {
+		req.UserID = identity.UserID
+	}
+	if req.Explain && TraceFrom(ctx) == nil {
+		ctx = WithTrace(ctx, domain.NewPipelineTrace())
+	}
+	if req.DryRun {
+		ctx = WithDryRun(ctx)
+	}
+	if err := g.checkShutdown(req.UserID); err != nil {
+		return nil, err
+	}
+
+	expanded, err := g.applyTenant(ctx, req)
+	var result *TierResult
+	if err == nil {
+		result, err = g.generate(ctx, expanded)
+	}
+	g.notify(ctx, req, result, err)
+	if g.moderation != nil && domain.ErrorCode(err) == domain.ErrCodeSafetyRejected {
+		g.moderation.RecordTenantViolation(TenantIDFrom(ctx), req.UserID, "safety_rejected", req.Prompt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Trace = TraceFrom(ctx)
+	result.DryRun = IsDryRun(ctx)
+
+	// A dry run drew nothing, so there is nothing to keep
+	if result.DryRun {
+		return result, nil
+	}
+
+	// A history failure should not throw away an image the user paid for
+	if err := g.repo.SaveGeneration(ctx, newGenerationRecord(ctx, req, result)); err != nil {
+		g.logger.Error("save generation history", err, "user_id", req.UserID, "tier", req.Tier)
+	}
+
+	return result, nil
+}
+
+// applyTenant enforces the caller's workspace policy on top of the global
+// checks every tier runs, expands a custom style into the prompt and picks
+// up the workspace's stage list for the tier
+func (g *ServiceGraph) applyTenant(ctx context.Context, req TierRequest) (TierRequest, error) {
+	tenant := g.tenant(ctx)
+
+	if req.CustomStyle != "" {
+		var style domain.CustomStyle
+		ok := false
+		if tenant != nil {
+			style, ok = tenant.Styles[req.CustomStyle]
+		}
+		if !ok {
+			return req, domain.NewAppError(nil, fmt.Sprintf("unknown custom style %q", req.CustomStyle), domain.ErrCodeInvalidRequest)
+		}
+
+		req.Prompt += ", " + style.Prompt
+		if req.Style == "" {
+			req.Style = style.Base
+		}
+	}
+
+	if tenant != nil {
+		if violations := tenant.Policy.Violations(req.Prompt, req.Style); len(violations) > 0 {
+			message := fmt.Sprintf("prompt not approved by workspace policy: %v", violations)
+			return req, domain.NewAppError(nil, message, domain.ErrCodeSafetyRejected)
+		}
+		if len(req.Stages) == 0 && req.Profile == "" {
+			req.Stages = tenant.Pipelines[string(req.Tier)]
+		}
+	}
+
+	return req, nil
+}
+
+// CheckPolicy reports the caller's workspace policy violations for a
+// prompt, for safety checks that do not generate
+func (g *ServiceGraph) CheckPolicy(ctx context.Context, prompt string, style string) []string {
+	if tenant := g.tenant(ctx); tenant != nil {
+		return tenant.Policy.Violations(prompt, style)
+	}
+	return nil
+}
+
+// tenant is the caller's registered workspace, or nil
+func (g *ServiceGraph) tenant(ctx context.Context) *domain.Tenant {
+	id := TenantIDFrom(ctx)
+	if g.tenants == nil || id == "" {
+		return nil
+	}
+	tenant, _ := g.tenants.Get(id)
+	return tenant
+}
+
+func (g *ServiceGraph) generate(ctx context.Context, req TierRequest) (*TierResult, error) {
+	var pipeline *Pipeline
+	if req.Profile != "" || len(req.Stages) > 0 {
+		var err error
+		if pipeline, err = g.requestPipeline(&req); err != nil {
+			return nil, domain.NewAppError(nil, err.Error(), domain.ErrCodeInvalidRequest)
+		}
+	}
+
+	selection, err := g.selectImage(req)
+	if err != nil {
+		return nil, domain.NewAppError(nil, err.Error(), domain.ErrCodeInvalidRequest)
+	}
+	if selection != nil {
+		ctx = withImageSelection(ctx, selection)
+	}
+
+	options := req.Options
+	if options.Style == "" {
+		options.Style = req.Style
+	}
+
+	if pipeline != nil {
+		return g.runPipeline(ctx, req, options, pipeline)
+	}
+
+	switch req.Tier {
+	case TierEnhanced:
+		resp, err := g.Enhanced.GenerateWithAnalysis(ctx, domain.GenerationRequest{
+			UserPrompt: req.Prompt,
+			Options:    options,
+			UserID:     req.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &TierResult{
+			Tier:        req.Tier,
+			FinalPrompt: resp.EnrichedPrompt,
+			ImageURL:    resp.ImageURL,
+			Analysis:    resp.Analysis,
+			Response:    resp,
+		}, nil
+
+	case TierFinal:
+		resp, err := g.Final.GenerateWithFinalReview(ctx, domain.GenerationRequest{
+			UserPrompt: req.Prompt,
+			Options:    options,
+			UserID:     req.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return finalTierResult(req.Tier, resp, resp), nil
+
+	case TierEnterprise:
+		resp, err := g.Enterprise.GenerateEnterpriseGrade(ctx, domain.EnterpriseRequest{
+			UserPrompt: req.Prompt,
+			Options:    options,
+			Style:      req.Style,
+			Filter:     req.Filter,
+			UserID:     req.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return finalTierResult(req.Tier, &resp.FinalGenerationResponse, resp), nil
+
+	case Tier3D:
+		resp, err := g.Enterprise3D.Generate3DProfessional(ctx, domain.Enterprise3DRequest{
+			UserPrompt: req.Prompt,
+			Options:    options,
+			Style:      req.Style,
+			Filter:     req.Filter,
+			ShotType:   req.ShotType,
+			Mood:       req.Mood,
+			UserID:     req.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return finalTierResult(req.Tier, &resp.FinalGenerationResponse, resp), nil
+
+	case TierMaster:
+		// options carries the requested style
+		resp, err := g.Master.generate(ctx, domain.MasterRequest{
+			UserPrompt: req.Prompt,
+			Options:    options,
+			ShotType:   req.ShotType,
+			Mood:       req.Mood,
+			UserID:     req.UserID,
+		}, req.Filter)
+		if err != nil {
+			return nil, err
+		}
+		return finalTierResult(req.Tier, &resp.FinalGenerationResponse, resp), nil
+	}
+
+	return nil, fmt.Errorf("unknown tier %q", req.Tier)
+}
+
+// GenerateComic runs a full comic from an outline
+func (g *ServiceGraph) GenerateComic(ctx context.Context, req ComicRequest) (*ai.ComicStory, error) {
+	if identity, ok := IdentityFrom(ctx); ok {
+		req.UserID = identity.UserID
+	}
+	if err := g.checkShutdown(req.UserID); err != nil {
+		return nil, err
+	}
+	return g.Comics.GenerateComic(ctx, req)
+}
+
+// checkShutdown refuses a user an administrator shut down
+func (g *ServiceGraph) checkShutdown(userID string) error {
+	if g.moderation != nil && userID != "" && g.moderation.IsShutDown(userID) {
+		return domain.NewAppError(nil, fmt.Sprintf("user %q is shut down by an administrator", userID), domain.ErrCodeForbidden)
+	}
+	return nil
+}
+
+func (g *ServiceGraph) notify(ctx context.Context, req TierRequest, result *TierResult, err error) {
+	// A cancelled run was abandoned by its caller and a dry run is only a
+	// preview; neither has an outcome
+	if g.notifier == nil || ctx.Err() != nil || IsDryRun(ctx) {
+		return
+	}
+
+	eventType, data := EventGenerationCompleted, interface{}(result)
+	switch {
+	case isSafetyRejection(err):
+		eventType, data = EventGenerationSafetyRejected, map[string]string{"tier": string(req.Tier), "error": err.Error()}
+	case err != nil:
+		eventType, data = EventGenerationFailed, map[string]string{"tier": string(req.Tier), "error": err.Error()}
+	}
+
+	g.notifier.Notify(ctx, newOutcomeEvent(ctx, eventType, req.WebhookURL, req.UserID, data))
+}
+
+// finalTierResult extracts the common fields every tier above enhanced
+// inherits from the final review response
+func finalTierResult(tier Tier, final *domain.FinalGenerationResponse, resp interface{}) *TierResult {
+	return &TierResult{
+		Tier:        tier,
+		FinalPrompt: final.FinalPrompt,
+		ImageURL:    final.GenerationResponse.ImageURL,
+		Analysis:    final.GenerationResponse.Analysis,
+		Response:    resp,
+	}
+}
+
+func newGenerationRecord(ctx context.Context, req TierRequest, result *TierResult) *domain.GenerationRecord {
+	record := &domain.GenerationRecord{
+		ID:          newRecordID(),
+		UserID:      req.UserID,
+		TenantID:    TenantIDFrom(ctx),
+		Tier:        string(result.Tier),
+		Prompt:      req.Prompt,
+		FinalPrompt: result.FinalPrompt,
+		Style:       req.Style,
+		ImageURL:    result.ImageURL,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	// The master agent picks a style when none was requested, so record
+	// the one it chose; a requested style is the one that was applied
+	var analysis *ai.MasterAnalysis
+	switch resp := result.Response.(type) {
+	case *domain.MasterResponse:
+		analysis = resp.MasterAnalysis
+	case *PipelineResponse:
+		analysis = resp.MasterAnalysis
+	}
+	if analysis != nil && req.Style == "" {
+		record.Style = analysis.ArtStyle.PrimaryStyle
+	}
+	if req.CustomStyle != "" {
+		record.Style = req.CustomStyle
+	}
+
+	if result.Analysis != nil {
+		record.Intent = result.Analysis.Intent
+		record.QualityScore = result.Analysis.QualityScore
+	}
+
+	return record
+}
+
+func newRecordID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+)
+
+type traceKey struct{}
+
+// WithTrace makes every service and agent called with ctx record its
+// prompt rewrites into trace
+func WithTrace(ctx context.Context, trace *domain.PipelineTrace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// TraceFrom returns the trace attached to ctx, or nil
+func TraceFrom(ctx context.Context) *domain.PipelineTrace {
+	trace, _ := ctx.Value(traceKey{}).(*domain.PipelineTrace)
+	return trace
+}
+
+func recordStep(ctx context.Context, stage string, agent string, before string, after string) {
+	TraceFrom(ctx).RecordStep(stage, agent, before, after)
+}
+
+// promptTracer adapts the context trace for the ai package. It returns a
+// nil interface, not a typed nil, when tracing is off.
+func promptTracer(ctx context.Context) ai.PromptTracer {
+	if trace := TraceFrom(ctx); trace != nil {
+		return trace
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package demo
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+)
+
+//go:embed scenarios/*.json
+var builtin embed.FS
+
+// ErrUnknownScenario is returned for a name the catalog does not contain
+var ErrUnknownScenario = errors.New("unknown demo scenario")
+
+// Catalog is the set of scenarios /demo/sample can run
+type Catalog struct {
+	scenarios map[string]*Scenario
+	names     []string
+	fallback  string
+}
+
+// BuiltinCatalog is the catalog shipped in the binary
+func BuiltinCatalog() (*Catalog, error) {
+	dir, err := fs.Sub(builtin, "scenarios")
+	if err != nil {
+		return nil, err
+	}
+	return LoadCatalog(dir)
+}
+
+// LoadCatalogDir reads every *.json scenario in dir, replacing the
+// builtin catalog
+func LoadCatalogDir(dir string) (*Catalog, error) {
+	return LoadCatalog(os.DirFS(dir))
+}
+
+// LoadCatalog reads one scenario per *.json file at the root of fsys and
+// rejects the whole catalog if any of them is invalid
+func LoadCatalog(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("demo catalog has no scenarios")
+	}
+
+	catalog := &Catalog{scenarios: make(map[string]*Scenario)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var scenario Scenario
+		if err := json.Unmarshal(data, &scenario); err != nil {
+			return nil, fmt.Errorf("demo scenario %s: %v", file, err)
+		}
+		if scenario.Name == "" {
+			scenario.Name = file[:len(file)-len(path.Ext(file))]
+		}
+		if err := scenario.validate(); err != nil {
+			return nil, fmt.Errorf("demo scenario %s: %v", file, err)
+		}
+		if _, taken := catalog.scenarios[scenario.Name]; taken {
+			return nil, fmt.Errorf("demo scenario %s: duplicate name %q", file, scenario.Name)
+		}
+
+		if scenario.Default {
+			if catalog.fallback != "" {
+				return nil, fmt.Errorf("demo scenario %s: %q is already the default", file, catalog.fallback)
+			}
+			catalog.fallback = scenario.Name
+		}
+
+		catalog.scenarios[scenario.Name] = &scenario
+		catalog.names = append(catalog.names, scenario.Name)
+	}
+
+	sort.Strings(catalog.names)
+	if catalog.fallback == "" {
+		catalog.fallback = catalog.names[0]
+	}
+	return catalog, nil
+}
+
+// Get looks a scenario up by name; "" is the default scenario
+func (c *Catalog) Get(name string) (*Scenario, error) {
+	if name == "" {
+		name = c.fallback
+	}
+	scenario, ok := c.scenarios[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScenario, name)
+	}
+	return scenario, nil
+}
+
+// Default is the scenario run when none is named
+func (c *Catalog) Default() string {
+	return c.fallback
+}
+
+// List returns every scenario sorted by name
+func (c *Catalog) List() []*Scenario {
+	scenarios := make([]*Scenario, len(c.names))
+	for i, name := range c.names {
+		scenarios[i] = c.scenarios[name]
+	}
+	return scenarios
+}
+
+// Names returns every scenario name, sorted
+func (c *Catalog) Names() []string {
+	return append([]string(nil), c.names...)
+}
@@ -0,0 +1,257 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+)
+
+// UserID owns the history records demo runs leave behind
+const UserID = "demo"
+
+// runTimeout bounds one scenario run; callers may give up sooner, but the
+// shared run carries on for whoever asks next
+const runTimeout = 5 * time.Minute
+
+// Generator is the part of the service graph a run needs
+type Generator interface {
+	Generate(ctx context.Context, req services.TierRequest) (*services.TierResult, error)
+}
+
+// Result is one measured run of a scenario
+type Result struct {
+	Scenario       *Scenario             `json:"scenario"`
+	FinalPrompt    string                `json:"final_prompt"`
+	ImageURL       string                `json:"image_url"`
+	Intent         string                `json:"intent,omitempty"`
+	QualityScore   float64               `json:"quality_score"`
+	Agents         []string              `json:"agents"`
+	AgentsUsed     int                   `json:"agents_used"`
+	Stages         []StageTiming         `json:"stages"`
+	ProcessingTime time.Duration         `json:"processing_time"`
+	Checks         []Check               `json:"checks"`
+	Passed         bool                  `json:"passed"`
+	RanAt          time.Time             `json:"ran_at"`
+	Trace          *domain.PipelineTrace `json:"trace,omitempty"`
+}
+
+// StageTiming is when a stage finished, measured from the start of the run
+type StageTiming struct {
+	Stage   string        `json:"stage"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Check is the outcome of one expectation
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	result  *Result
+	err     error
+	expires time.Time
+}
+
+// expired is false while the run is in progress
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+func (e *cacheEntry) finished() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Runner runs scenarios and caches each result for ttl. Concurrent
+// requests for the same scenario share one run, so a burst of visitors
+// costs a single generation.
+type Runner struct {
+	catalog   *Catalog
+	generator Generator
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+func NewRunner(catalog *Catalog, generator Generator, ttl time.Duration) *Runner {
+	return &Runner{
+		catalog:   catalog,
+		generator: generator,
+		ttl:       ttl,
+		cache:     make(map[string]*cacheEntry),
+	}
+}
+
+func (r *Runner) Catalog() *Catalog {
+	return r.catalog
+}
+
+// Run returns the scenario's result and whether it came from the cache,
+// which includes joining a run another request started, so only the
+// request that ran the scenario reports false. Failed runs are not cached.
+func (r *Runner) Run(ctx context.Context, name string) (*Result, bool, error) {
+	scenario, err := r.catalog.Get(name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.mu.Lock()
+	entry, ok := r.cache[scenario.Name]
+	cached := ok && !entry.expired(time.Now())
+	if !cached {
+		entry = &cacheEntry{done: make(chan struct{})}
+		r.cache[scenario.Name] = entry
+		go r.fill(ctx, scenario, entry)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+
+	return entry.result, cached, entry.err
+}
+
+// Invalidate drops every cached result
+func (r *Runner) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Runs still in progress stay shared until they finish
+	for name, entry := range r.cache {
+		if entry.finished() {
+			delete(r.cache, name)
+		}
+	}
+}
+
+func (r *Runner) fill(ctx context.Context, scenario *Scenario, entry *cacheEntry) {
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runTimeout)
+	defer cancel()
+
+	entry.result, entry.err = r.run(runCtx, scenario)
+
+	r.mu.Lock()
+	if entry.err != nil {
+		delete(r.cache, scenario.Name)
+	} else {
+		entry.expires = time.Now().Add(r.ttl)
+	}
+	r.mu.Unlock()
+
+	close(entry.done)
+}
+
+func (r *Runner) run(ctx context.Context, scenario *Scenario) (*Result, error) {
+	started := time.Now()
+
+	var mu sync.Mutex
+	var stages []StageTiming
+	ctx = services.WithIdentity(ctx, domain.Identity{UserID: UserID})
+	ctx = services.WithProgress(ctx, func(event domain.ProgressEvent) {
+		mu.Lock()
+		stages = append(stages, StageTiming{Stage: event.Stage, Elapsed: time.Since(started)})
+		mu.Unlock()
+	})
+
+	generated, err := r.generator.Generate(ctx, scenario.Request())
+	if err != nil {
+		return nil, fmt.Errorf("demo scenario %s: %w", scenario.Name, err)
+	}
+
+	result := &Result{
+		Scenario:       scenario,
+		FinalPrompt:    generated.FinalPrompt,
+		ImageURL:       generated.ImageURL,
+		Stages:         stages,
+		ProcessingTime: time.Since(started),
+		RanAt:          started.UTC(),
+		Trace:          generated.Trace,
+	}
+	if generated.Analysis != nil {
+		result.Intent = generated.Analysis.Intent
+		result.QualityScore = generated.Analysis.QualityScore
+	}
+	result.Agents = agentsIn(generated.Trace)
+	result.AgentsUsed = len(result.Agents)
+	result.Checks = checkExpectations(scenario.Expect, result)
+
+	result.Passed = true
+	for _, check := range result.Checks {
+		result.Passed = result.Passed && check.Passed
+	}
+
+	return result, nil
+}
+
+// agentsIn lists the distinct agents that took part, in the order they
+// first appeared
+func agentsIn(trace *domain.PipelineTrace) []string {
+	agents := make([]string, 0)
+	if trace == nil {
+		return agents
+	}
+
+	seen := make(map[string]bool)
+	for _, step := range trace.Steps {
+		if step.Agent != "" && !seen[step.Agent] {
+			seen[step.Agent] = true
+			agents = append(agents, step.Agent)
+		}
+	}
+	return agents
+}
+
+func checkExpectations(expect Expectations, result *Result) []Check {
+	checks := make([]Check, 0)
+
+	if expect.MinQualityScore > 0 {
+		checks = append(checks, Check{
+			Name:   "min_quality_score",
+			Passed: result.QualityScore >= expect.MinQualityScore,
+			Detail: fmt.Sprintf("quality score %.2f, expected at least %.2f", result.QualityScore, expect.MinQualityScore),
+		})
+	}
+
+	if expect.Intent != "" {
+		checks = append(checks, Check{
+			Name:   "intent",
+			Passed: result.Intent == expect.Intent,
+			Detail: fmt.Sprintf("intent %q, expected %q", result.Intent, expect.Intent),
+		})
+	}
+
+	if expect.MinAgents > 0 {
+		checks = append(checks, Check{
+			Name:   "min_agents",
+			Passed: result.AgentsUsed >= expect.MinAgents,
+			Detail: fmt.Sprintf("%d agents, expected at least %d", result.AgentsUsed, expect.MinAgents),
+		})
+	}
+
+	prompt := strings.ToLower(result.FinalPrompt)
+	for _, phrase := range expect.PromptContains {
+		checks = append(checks, Check{
+			Name:   "prompt_contains",
+			Passed: strings.Contains(prompt, strings.ToLower(phrase)),
+			Detail: fmt.Sprintf("final prompt mentions %q", phrase),
+		})
+	}
+
+	return checks
+}
@@ -0,0 +1,92 @@
+// Package demo runs the scenario catalog behind /demo/sample through the
+// real service graph, so the numbers a demo shows are the product's own.
+package demo
+
+import (
+	"fmt"
+	"strings"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/services"
+)
+
+// Scenario is one catalog entry: an input and the tier settings to run it
+// with, plus what a healthy run should produce
+type Scenario struct {
+	Name        string        `json:"name"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Default     bool          `json:"default,omitempty"`
+	Prompt      string        `json:"prompt"`
+	Tier        services.Tier `json:"tier"`
+	Style       string        `json:"style,omitempty"`
+	Filter      string        `json:"filter,omitempty"`
+	ShotType    string        `json:"shot_type,omitempty"`
+	Mood        string        `json:"mood,omitempty"`
+	Expect      Expectations  `json:"expect"`
+}
+
+// Expectations are checked against every run; a failed expectation is
+// reported, not hidden
+type Expectations struct {
+	MinQualityScore float64  `json:"min_quality_score,omitempty"`
+	Intent          string   `json:"intent,omitempty"`
+	MinAgents       int      `json:"min_agents,omitempty"`
+	PromptContains  []string `json:"prompt_contains,omitempty"`
+}
+
+// Request is the tier request the scenario runs as. Explain is always on:
+// the trace is where the agent count comes from.
+func (s *Scenario) Request() services.TierRequest {
+	return services.TierRequest{
+		Tier:     s.Tier,
+		Prompt:   s.Prompt,
+		Style:    s.Style,
+		Filter:   s.Filter,
+		ShotType: s.ShotType,
+		Mood:     s.Mood,
+		UserID:   UserID,
+		Explain:  true,
+	}
+}
+
+func (s *Scenario) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(s.Prompt) == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	if _, err := services.ParseTier(string(s.Tier)); err != nil {
+		return err
+	}
+
+	styles := ai.NewArtStyleEngine()
+	for field, check := range map[string]struct {
+		value   string
+		allowed []string
+	}{
+		"style":     {s.Style, styles.Styles()},
+		"filter":    {s.Filter, styles.Filters()},
+		"shot_type": {s.ShotType, ai.ShotTypes},
+		"mood":      {s.Mood, ai.Moods},
+	} {
+		if check.value != "" && !contains(check.allowed, check.value) {
+			return fmt.Errorf("unknown %s %q", field, check.value)
+		}
+	}
+
+	if s.Expect.MinQualityScore < 0 || s.Expect.MinQualityScore > 1 {
+		return fmt.Errorf("expect.min_quality_score must be between 0 and 1")
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
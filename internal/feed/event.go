@@ -0,0 +1,60 @@
+// Package feed pushes live operational events (agent status, system
+// health, alerts and moderation violations) to subscribers such as the
+// dashboard's WebSocket connection.
+package feed
+
+import "time"
+
+// Topics a subscriber can choose from
+const (
+	TopicStatus     = "status"
+	TopicHealth     = "health"
+	TopicAlerts     = "alerts"
+	TopicModeration = "moderation"
+)
+
+// Topics lists every topic
+var Topics = []string{TopicStatus, TopicHealth, TopicAlerts, TopicModeration}
+
+// AdminTopics carry data only operators may see: alerts expose agent
+// metrics and violations contain user prompts
+var AdminTopics = []string{TopicAlerts, TopicModeration}
+
+// Event types
+const (
+	EventStatusSnapshot = "status.snapshot"
+	EventStatusDelta    = "status.delta"
+	EventHealthSnapshot = "health.snapshot"
+	EventHealthChanged  = "health.changed"
+	EventAlert          = "alert"
+	EventViolation      = "violation"
+)
+
+// Event is one message on the feed. Seq increases across all topics, so a
+// client can tell from a gap that it missed something.
+type Event struct {
+	Seq       uint64      `json:"seq"`
+	Topic     string      `json:"topic"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// ValidTopic reports whether name is a known topic
+func ValidTopic(name string) bool {
+	return contains(Topics, name)
+}
+
+// AdminTopic reports whether name needs the admin role
+func AdminTopic(name string) bool {
+	return contains(AdminTopics, name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
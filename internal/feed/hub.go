@@ -0,0 +1,244 @@
+package feed
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSlowConsumer closes a subscription whose buffer stayed full for longer
+// than the hub's SlowTimeout
+var ErrSlowConsumer = errors.New("subscriber too slow")
+
+// ErrHubClosed closes every subscription when the hub shuts down
+var ErrHubClosed = errors.New("feed closed")
+
+type Config struct {
+	// Buffer is how many events a subscriber may fall behind by before
+	// events are dropped for it
+	Buffer int
+	// SlowTimeout disconnects a subscriber that keeps its buffer full
+	SlowTimeout time.Duration
+}
+
+// Hub fans events out to subscriptions. Publishing never blocks: a
+// subscriber that cannot keep up loses events (and is told how many), and
+// one that stays stuck is disconnected, so a slow dashboard cannot hold up
+// the agents it is watching.
+type Hub struct {
+	config Config
+	seq    atomic.Uint64
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub(config Config) *Hub {
+	if config.Buffer < 1 {
+		config.Buffer = 1
+	}
+	return &Hub{
+		config: config,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe starts delivering events on topics. The caller must Close the
+// subscription when done with it.
+func (h *Hub) Subscribe(topics []string) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		events: make(chan Event, h.config.Buffer),
+		done:   make(chan struct{}),
+		topics: make(map[string]bool),
+	}
+	sub.SetTopics(topics)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.close(ErrHubClosed)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Subscribers is the number of open subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subs)
+}
+
+// Publish sends an event to every subscriber of its topic
+func (h *Hub) Publish(topic string, eventType string, data interface{}) {
+	event := h.NewEvent(topic, eventType, data)
+
+	h.mu.RLock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.RUnlock()
+
+	now := time.Now()
+	for _, sub := range subs {
+		sub.offer(event, now)
+	}
+}
+
+// NewEvent stamps an event with the next sequence number without
+// publishing it, for snapshots sent to a single subscriber
+func (h *Hub) NewEvent(topic string, eventType string, data interface{}) Event {
+	return Event{
+		Seq:       h.seq.Add(1),
+		Topic:     topic,
+		Type:      eventType,
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Close ends every subscription; later subscriptions start closed
+func (h *Hub) Close() {
+	h.mu.Lock()
+	subs := h.subs
+	h.subs = make(map[*Subscription]struct{})
+	h.closed = true
+	h.mu.Unlock()
+
+	for sub := range subs {
+		sub.close(ErrHubClosed)
+	}
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subs, sub)
+}
+
+// Subscription is one subscriber's view of the hub
+type Subscription struct {
+	hub    *Hub
+	events chan Event
+	done   chan struct{}
+
+	mu        sync.Mutex
+	topics    map[string]bool
+	dropped   int
+	fullSince time.Time
+	err       error
+}
+
+// Events delivers the subscriber's events in publish order
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the hub ends the subscription; Err says why
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// SetTopics replaces the topics the subscriber receives
+func (s *Subscription) SetTopics(topics []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.topics = make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+}
+
+// Topics returns the current topics
+func (s *Subscription) Topics() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topics := make([]string, 0, len(s.topics))
+	for _, topic := range Topics {
+		if s.topics[topic] {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Dropped returns how many events were dropped since the last call
+func (s *Subscription) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := s.dropped
+	s.dropped = 0
+	return dropped
+}
+
+// Close unsubscribes
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+	s.close(nil)
+}
+
+func (s *Subscription) offer(event Event, now time.Time) {
+	s.mu.Lock()
+	if s.isDone() || !s.topics[event.Topic] {
+		s.mu.Unlock()
+		return
+	}
+
+	select {
+	case s.events <- event:
+		s.fullSince = time.Time{}
+		s.mu.Unlock()
+		return
+	default:
+	}
+
+	s.dropped++
+	if s.fullSince.IsZero() {
+		s.fullSince = now
+	}
+	slow := s.hub.config.SlowTimeout > 0 && now.Sub(s.fullSince) > s.hub.config.SlowTimeout
+	s.mu.Unlock()
+
+	if slow {
+		s.hub.remove(s)
+		s.close(ErrSlowConsumer)
+	}
+}
+
+func (s *Subscription) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isDone() {
+		return
+	}
+	s.err = err
+	close(s.done)
+}
+
+func (s *Subscription) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
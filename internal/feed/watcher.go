@@ -0,0 +1,162 @@
+package feed
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+)
+
+// Watcher turns the polled status APIs into change events and forwards
+// alerts and moderation violations as they are raised
+type Watcher struct {
+	hub      *Hub
+	manager  *management.ManagerAgent
+	health   *management.HealthMonitor
+	interval time.Duration
+
+	mu           sync.Mutex
+	status       *management.SystemStatus
+	healthStatus string
+}
+
+// NewWatcher subscribes to alerts and violations straight away; status
+// and health changes are found by polling once Run is called
+func NewWatcher(hub *Hub, manager *management.ManagerAgent, health *management.HealthMonitor, moderation *ai.ModerationHistory, interval time.Duration) *Watcher {
+	w := &Watcher{
+		hub:      hub,
+		manager:  manager,
+		health:   health,
+		interval: interval,
+	}
+
+	manager.OnAlert(func(alert management.Alert) {
+		hub.Publish(TopicAlerts, EventAlert, alert)
+	})
+	health.OnAlert(func(alert management.Alert) {
+		hub.Publish(TopicAlerts, EventAlert, alert)
+	})
+	moderation.Subscribe(func(violation ai.ModerationViolation) {
+		hub.Publish(TopicModeration, EventViolation, violation)
+	})
+
+	return w
+}
+
+// Run polls until ctx is cancelled. Polling is skipped while nobody is
+// subscribed.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.hub.Subscribers() > 0 {
+				w.poll()
+			}
+		}
+	}
+}
+
+// Snapshot is the current state of a topic, sent to a subscriber when it
+// joins or asks to resync. Event-only topics have no snapshot.
+func (w *Watcher) Snapshot(topic string) (Event, bool) {
+	switch topic {
+	case TopicStatus:
+		return w.hub.NewEvent(TopicStatus, EventStatusSnapshot, w.manager.GetSystemStatus()), true
+	case TopicHealth:
+		return w.hub.NewEvent(TopicHealth, EventHealthSnapshot, w.health.CheckSystemHealth()), true
+	}
+	return Event{}, false
+}
+
+func (w *Watcher) poll() {
+	status := w.manager.GetSystemStatus()
+	health := w.health.CheckSystemHealth()
+
+	w.mu.Lock()
+	delta, changed := statusDelta(w.status, status)
+	w.status = status
+	previousHealth := w.healthStatus
+	w.healthStatus = health.Status
+	w.mu.Unlock()
+
+	if changed {
+		w.hub.Publish(TopicStatus, EventStatusDelta, delta)
+	}
+	if previousHealth != "" && previousHealth != health.Status {
+		w.hub.Publish(TopicHealth, EventHealthChanged, HealthChange{
+			From:   previousHealth,
+			To:     health.Status,
+			Health: health,
+		})
+	}
+}
+
+// HealthChange is the data of a health.changed event
+type HealthChange struct {
+	From   string                   `json:"from"`
+	To     string                   `json:"to"`
+	Health *management.SystemHealth `json:"health"`
+}
+
+// StatusDelta is the data of a status.delta event: the summary fields
+// that changed, every agent whose status changed, and agents that left
+type StatusDelta struct {
+	OverallHealth      *float64                 `json:"overall_health,omitempty"`
+	AveragePerformance *float64                 `json:"average_performance,omitempty"`
+	SystemLoad         *float64                 `json:"system_load,omitempty"`
+	TotalAgents        *int                     `json:"total_agents,omitempty"`
+	Uptime             time.Duration            `json:"uptime"`
+	Agents             []management.AgentStatus `json:"agents,omitempty"`
+	Removed            []string                 `json:"removed,omitempty"`
+}
+
+// statusDelta compares two polls. Uptime always moves, so it is carried
+// along but never counts as a change on its own.
+func statusDelta(previous *management.SystemStatus, next *management.SystemStatus) (*StatusDelta, bool) {
+	if previous == nil {
+		previous = &management.SystemStatus{}
+	}
+
+	delta := &StatusDelta{Uptime: next.Uptime}
+	changed := false
+
+	if next.OverallHealth != previous.OverallHealth {
+		delta.OverallHealth, changed = &next.OverallHealth, true
+	}
+	if next.AveragePerformance != previous.AveragePerformance {
+		delta.AveragePerformance, changed = &next.AveragePerformance, true
+	}
+	if next.SystemLoad != previous.SystemLoad {
+		delta.SystemLoad, changed = &next.SystemLoad, true
+	}
+	if next.TotalAgents != previous.TotalAgents {
+		delta.TotalAgents, changed = &next.TotalAgents, true
+	}
+
+	before := make(map[string]management.AgentStatus, len(previous.Agents))
+	for _, agent := range previous.Agents {
+		before[agent.ID] = agent
+	}
+	for _, agent := range next.Agents {
+		if old, ok := before[agent.ID]; !ok || old != agent {
+			delta.Agents = append(delta.Agents, agent)
+			changed = true
+		}
+		delete(before, agent.ID)
+	}
+	for id := range before {
+		delta.Removed = append(delta.Removed, id)
+		changed = true
+	}
+	sort.Strings(delta.Removed)
+
+	return delta, changed
+}
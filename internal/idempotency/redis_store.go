@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore shares records between API nodes, so a retry that lands on a
+// different node is still recognised
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisStore connects using a redis:// URL, e.g. redis://redis:6379/0
+func NewRedisStore(url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisStore{
+		client:    redis.NewClient(options),
+		keyPrefix: "geminizer:idempotency:",
+	}, nil
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, key string, record *Record, ttl time.Duration) (*Record, bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reserved, err := s.client.SetNX(ctx, s.keyPrefix+key, data, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if reserved {
+		return nil, true, nil
+	}
+
+	existing, err := s.client.Get(ctx, s.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Expired between SETNX and GET; treat as taken and let the
+		// client retry rather than racing for the key again
+		return &Record{RequestHash: record.RequestHash}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var found Record
+	if err := json.Unmarshal(existing, &found); err != nil {
+		return nil, false, err
+	}
+	return &found, false, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.keyPrefix+key, data, ttl).Err()
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.keyPrefix+key).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
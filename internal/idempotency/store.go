@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is what is kept per key: a hash of the request that first used
+// it and, once that request finished, the response to replay
+type Record struct {
+	RequestHash string      `json:"request_hash"`
+	Completed   bool        `json:"completed"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// Store persists records for a TTL
+type Store interface {
+	// Reserve creates a pending record for key unless one exists. It
+	// returns the existing record and false when the key is taken.
+	Reserve(ctx context.Context, key string, record *Record, ttl time.Duration) (*Record, bool, error)
+	// Complete replaces the pending record with the finished one
+	Complete(ctx context.Context, key string, record *Record, ttl time.Duration) error
+	// Release drops a pending record so the key can be retried
+	Release(ctx context.Context, key string) error
+}
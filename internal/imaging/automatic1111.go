@@ -0,0 +1,96 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+const defaultAutomatic1111Steps = 30
+
+// automatic1111Sizes are SDXL's trained resolutions for each ratio
+var automatic1111Sizes = map[string][2]int{
+	"1:1":  {1024, 1024},
+	"3:4":  {896, 1152},
+	"4:3":  {1152, 896},
+	"9:16": {768, 1344},
+	"16:9": {1344, 768},
+}
+
+type Automatic1111Config struct {
+	BaseURL string
+	Steps   int
+}
+
+// Automatic1111 draws through a local Stable Diffusion server's txt2img
+// API. ComfyUI installs serving the same API work as well.
+type Automatic1111 struct {
+	baseURL string
+	steps   int
+	client  *http.Client
+}
+
+func NewAutomatic1111(config Automatic1111Config, client *http.Client) *Automatic1111 {
+	steps := config.Steps
+	if steps < 1 {
+		steps = defaultAutomatic1111Steps
+	}
+	return &Automatic1111{
+		baseURL: trimBaseURL(config.BaseURL, "http://127.0.0.1:7860"),
+		steps:   steps,
+		client:  client,
+	}
+}
+
+func (a *Automatic1111) Name() string { return "automatic1111" }
+
+// Capabilities: long prompts are split into chunks by the server, so
+// there is no length limit
+func (a *Automatic1111) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{
+		MaxPromptLength: 0,
+		NegativePrompt:  true,
+		AspectRatios:    []string{"1:1", "3:4", "4:3", "9:16", "16:9"},
+	}
+}
+
+type automatic1111Request struct {
+	Prompt         string `json:"prompt"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	Steps          int    `json:"steps"`
+}
+
+type automatic1111Response struct {
+	Images []string `json:"images"`
+}
+
+func (a *Automatic1111) Generate(ctx context.Context, req domain.ImageRequest) (*domain.Image, error) {
+	size, ok := automatic1111Sizes[req.AspectRatio]
+	if !ok {
+		size = automatic1111Sizes["1:1"]
+	}
+
+	var resp automatic1111Response
+	err := postJSON(ctx, a.client, a.baseURL+"/sdapi/v1/txt2img", nil, automatic1111Request{
+		Prompt:         req.Prompt,
+		NegativePrompt: req.NegativePrompt,
+		Width:          size[0],
+		Height:         size[1],
+		Steps:          a.steps,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Images) == 0 || resp.Images[0] == "" {
+		return nil, fmt.Errorf("no image returned")
+	}
+	return &domain.Image{
+		URL:      dataURL("image/png", resp.Images[0]),
+		MimeType: "image/png",
+	}, nil
+}
@@ -0,0 +1,80 @@
+package imaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+func TestAutomatic1111RequestMapping(t *testing.T) {
+	var got automatic1111Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sdapi/v1/txt2img" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"images":["aW1n"]}`))
+	}))
+	defer server.Close()
+
+	a1111 := NewAutomatic1111(Automatic1111Config{BaseURL: server.URL}, server.Client())
+	image, err := a1111.Generate(context.Background(), domain.ImageRequest{
+		Prompt:         "a lighthouse",
+		NegativePrompt: "blurry",
+		AspectRatio:    "9:16",
+	})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	want := automatic1111Request{
+		Prompt:         "a lighthouse",
+		NegativePrompt: "blurry",
+		Width:          768,
+		Height:         1344,
+		Steps:          defaultAutomatic1111Steps,
+	}
+	if got != want {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+	if image.URL != "data:image/png;base64,aW1n" || image.MimeType != "image/png" {
+		t.Errorf("image = %+v", image)
+	}
+}
+
+func TestAutomatic1111UnknownRatioIsSquare(t *testing.T) {
+	var got automatic1111Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"images":["aW1n"]}`))
+	}))
+	defer server.Close()
+
+	a1111 := NewAutomatic1111(Automatic1111Config{BaseURL: server.URL, Steps: 12}, server.Client())
+	if _, err := a1111.Generate(context.Background(), domain.ImageRequest{Prompt: "x", AspectRatio: "21:9"}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if got.Width != 1024 || got.Height != 1024 || got.Steps != 12 {
+		t.Errorf("request = %+v, want 1024x1024 in 12 steps", got)
+	}
+}
+
+func TestAutomatic1111ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "CUDA out of memory", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := NewAutomatic1111(Automatic1111Config{BaseURL: server.URL}, server.Client()).Generate(context.Background(), domain.ImageRequest{Prompt: "x"})
+	var status *StatusError
+	if !errors.As(err, &status) || status.Code != http.StatusInternalServerError || !status.Retryable() {
+		t.Fatalf("err = %v, want a retryable 500", err)
+	}
+}
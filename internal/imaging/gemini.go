@@ -0,0 +1,105 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+const (
+	defaultGeminiBaseURL = "https://generativelanguage.googleapis.com"
+	defaultGeminiModel   = "imagen-3.0-generate-002"
+)
+
+type GeminiConfig struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+}
+
+// Gemini draws with an Imagen model through the Gemini API's predict
+// endpoint
+type Gemini struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewGemini(config GeminiConfig, client *http.Client) *Gemini {
+	model := config.Model
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &Gemini{
+		baseURL: trimBaseURL(config.BaseURL, defaultGeminiBaseURL),
+		apiKey:  config.APIKey,
+		model:   model,
+		client:  client,
+	}
+}
+
+func (g *Gemini) Name() string { return "gemini" }
+
+// Capabilities: Imagen reads about 480 tokens of prompt and has no
+// negative prompt
+func (g *Gemini) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{
+		MaxPromptLength: 1900,
+		NegativePrompt:  false,
+		AspectRatios:    []string{"1:1", "3:4", "4:3", "9:16", "16:9"},
+	}
+}
+
+type geminiRequest struct {
+	Instances  []geminiInstance `json:"instances"`
+	Parameters geminiParameters `json:"parameters"`
+}
+
+type geminiInstance struct {
+	Prompt string `json:"prompt"`
+}
+
+type geminiParameters struct {
+	SampleCount int    `json:"sampleCount"`
+	AspectRatio string `json:"aspectRatio,omitempty"`
+}
+
+type geminiResponse struct {
+	Predictions []struct {
+		BytesBase64Encoded string `json:"bytesBase64Encoded"`
+		MimeType           string `json:"mimeType"`
+	} `json:"predictions"`
+}
+
+func (g *Gemini) Generate(ctx context.Context, req domain.ImageRequest) (*domain.Image, error) {
+	endpoint := fmt.Sprintf("%s/v1beta/models/%s:predict", g.baseURL, url.PathEscape(g.model))
+	header := http.Header{}
+	if g.apiKey != "" {
+		header.Set("x-goog-api-key", g.apiKey)
+	}
+
+	var resp geminiResponse
+	err := postJSON(ctx, g.client, endpoint, header, geminiRequest{
+		Instances:  []geminiInstance{{Prompt: req.Prompt}},
+		Parameters: geminiParameters{SampleCount: 1, AspectRatio: req.AspectRatio},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Imagen returns no prediction, rather than an error, when its own
+	// safety filter drops the image
+	if len(resp.Predictions) == 0 || resp.Predictions[0].BytesBase64Encoded == "" {
+		return nil, refusal("no image returned; the prompt may have been filtered")
+	}
+	prediction := resp.Predictions[0]
+	return &domain.Image{
+		URL:      dataURL(prediction.MimeType, prediction.BytesBase64Encoded),
+		MimeType: prediction.MimeType,
+		Model:    g.model,
+	}, nil
+}
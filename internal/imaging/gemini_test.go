@@ -0,0 +1,74 @@
+package imaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+func TestGeminiRequestMapping(t *testing.T) {
+	var got geminiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1beta/models/imagen-test:predict" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if key := r.Header.Get("x-goog-api-key"); key != "secret" {
+			t.Errorf("x-goog-api-key = %q", key)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"predictions":[{"bytesBase64Encoded":"aW1n","mimeType":"image/jpeg"}]}`))
+	}))
+	defer server.Close()
+
+	gemini := NewGemini(GeminiConfig{BaseURL: server.URL + "/", APIKey: "secret", Model: "imagen-test"}, server.Client())
+	image, err := gemini.Generate(context.Background(), domain.ImageRequest{Prompt: "a lighthouse", AspectRatio: "16:9"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	if len(got.Instances) != 1 || got.Instances[0].Prompt != "a lighthouse" {
+		t.Errorf("instances = %+v", got.Instances)
+	}
+	if got.Parameters.SampleCount != 1 || got.Parameters.AspectRatio != "16:9" {
+		t.Errorf("parameters = %+v", got.Parameters)
+	}
+	if image.URL != "data:image/jpeg;base64,aW1n" || image.MimeType != "image/jpeg" || image.Model != "imagen-test" {
+		t.Errorf("image = %+v", image)
+	}
+}
+
+func TestGeminiFilteredImageIsARefusal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"predictions":[]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewGemini(GeminiConfig{BaseURL: server.URL}, server.Client()).Generate(context.Background(), domain.ImageRequest{Prompt: "x"})
+	var r refusal
+	if !errors.As(err, &r) || r.Retryable() {
+		t.Fatalf("err = %v, want a non-retryable refusal", err)
+	}
+}
+
+func TestGeminiErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exhausted", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewGemini(GeminiConfig{BaseURL: server.URL}, server.Client()).Generate(context.Background(), domain.ImageRequest{Prompt: "x"})
+	var status *StatusError
+	if !errors.As(err, &status) {
+		t.Fatalf("err = %v, want a StatusError", err)
+	}
+	if status.Code != http.StatusTooManyRequests || status.Body != "quota exhausted" || !status.Retryable() {
+		t.Errorf("status = %+v, retryable %v", status, status.Retryable())
+	}
+}
@@ -0,0 +1,114 @@
+// Package imaging adapts image generation backends to
+// services.ImageProvider. Every adapter takes its base URL from its
+// config, so it can be pointed at a local stand-in server.
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+// Config selects the providers to register. An adapter is registered when
+// its base URL or API key is set.
+type Config struct {
+	Timeout       time.Duration // per image, for every provider
+	Gemini        GeminiConfig
+	Automatic1111 Automatic1111Config
+	OpenAI        OpenAIConfig
+}
+
+// Providers builds the configured adapters
+func Providers(config Config) []services.ImageProvider {
+	client := &http.Client{Timeout: config.Timeout}
+
+	var providers []services.ImageProvider
+	if config.Gemini.APIKey != "" || config.Gemini.BaseURL != "" {
+		providers = append(providers, NewGemini(config.Gemini, client))
+	}
+	if config.Automatic1111.BaseURL != "" {
+		providers = append(providers, NewAutomatic1111(config.Automatic1111, client))
+	}
+	if config.OpenAI.APIKey != "" || config.OpenAI.BaseURL != "" {
+		providers = append(providers, NewOpenAI(config.OpenAI, client))
+	}
+	return providers
+}
+
+// postJSON sends body to url and decodes a 2xx response into out. The
+// start of an error response is kept, since backends explain themselves
+// there.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(excerpt))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %v", err)
+	}
+	return nil
+}
+
+// StatusError is an error status a backend answered with
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.Code, e.Body)
+}
+
+// Retryable is false for a request the backend refused; only timeouts,
+// rate limits and server errors may pass on another attempt
+func (e *StatusError) Retryable() bool {
+	return e.Code == http.StatusRequestTimeout || e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// refusal is an answer a backend gave instead of an image; another
+// attempt would get the same answer
+type refusal string
+
+func (r refusal) Error() string   { return string(r) }
+func (r refusal) Retryable() bool { return false }
+
+func dataURL(mimeType string, encoded string) string {
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	return "data:" + mimeType + ";base64," + encoded
+}
+
+func trimBaseURL(url string, fallback string) string {
+	if url == "" {
+		url = fallback
+	}
+	return strings.TrimRight(url, "/")
+}
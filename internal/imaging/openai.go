@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com"
+	defaultOpenAIModel   = "dall-e-3"
+)
+
+// openAISizes are the sizes the images API accepts for each ratio
+var openAISizes = map[string]string{
+	"1:1":  "1024x1024",
+	"16:9": "1792x1024",
+	"9:16": "1024x1792",
+}
+
+type OpenAIConfig struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+}
+
+// OpenAI draws through an OpenAI-style images API
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+func NewOpenAI(config OpenAIConfig, client *http.Client) *OpenAI {
+	model := config.Model
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	return &OpenAI{
+		baseURL: trimBaseURL(config.BaseURL, defaultOpenAIBaseURL),
+		apiKey:  config.APIKey,
+		model:   model,
+		client:  client,
+	}
+}
+
+func (o *OpenAI) Name() string { return "openai" }
+
+func (o *OpenAI) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{
+		MaxPromptLength: 4000,
+		NegativePrompt:  false,
+		AspectRatios:    []string{"1:1", "9:16", "16:9"},
+	}
+}
+
+type openAIRequest struct {
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n"`
+	Size           string `json:"size"`
+	ResponseFormat string `json:"response_format"`
+}
+
+type openAIResponse struct {
+	Data []struct {
+		URL     string `json:"url"`
+		B64JSON string `json:"b64_json"`
+	} `json:"data"`
+}
+
+func (o *OpenAI) Generate(ctx context.Context, req domain.ImageRequest) (*domain.Image, error) {
+	size, ok := openAISizes[req.AspectRatio]
+	if !ok {
+		size = openAISizes["1:1"]
+	}
+	header := http.Header{}
+	if o.apiKey != "" {
+		header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	var resp openAIResponse
+	err := postJSON(ctx, o.client, o.baseURL+"/v1/images/generations", header, openAIRequest{
+		Model:          o.model,
+		Prompt:         req.Prompt,
+		N:              1,
+		Size:           size,
+		ResponseFormat: "b64_json",
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("no image returned")
+	}
+	image := &domain.Image{Model: o.model}
+	switch data := resp.Data[0]; {
+	case data.B64JSON != "":
+		image.URL, image.MimeType = dataURL("image/png", data.B64JSON), "image/png"
+	case data.URL != "":
+		image.URL = data.URL
+	default:
+		return nil, fmt.Errorf("no image returned")
+	}
+	return image, nil
+}
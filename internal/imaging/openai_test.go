@@ -0,0 +1,77 @@
+package imaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+func TestOpenAIRequestMapping(t *testing.T) {
+	var got openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/images/generations" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"data":[{"b64_json":"aW1n"}]}`))
+	}))
+	defer server.Close()
+
+	openai := NewOpenAI(OpenAIConfig{BaseURL: server.URL, APIKey: "secret"}, server.Client())
+	image, err := openai.Generate(context.Background(), domain.ImageRequest{Prompt: "a lighthouse", AspectRatio: "16:9"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	want := openAIRequest{
+		Model:          defaultOpenAIModel,
+		Prompt:         "a lighthouse",
+		N:              1,
+		Size:           "1792x1024",
+		ResponseFormat: "b64_json",
+	}
+	if got != want {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+	if image.URL != "data:image/png;base64,aW1n" || image.MimeType != "image/png" || image.Model != defaultOpenAIModel {
+		t.Errorf("image = %+v", image)
+	}
+}
+
+func TestOpenAIURLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"url":"https://images.example/1.png"}]}`))
+	}))
+	defer server.Close()
+
+	image, err := NewOpenAI(OpenAIConfig{BaseURL: server.URL}, server.Client()).Generate(context.Background(), domain.ImageRequest{Prompt: "x"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if image.URL != "https://images.example/1.png" {
+		t.Errorf("URL = %q", image.URL)
+	}
+}
+
+func TestOpenAIRefusedRequestIsNotRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":"content_policy_violation"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAI(OpenAIConfig{BaseURL: server.URL}, server.Client()).Generate(context.Background(), domain.ImageRequest{Prompt: "x"})
+	var status *StatusError
+	if !errors.As(err, &status) || status.Code != http.StatusBadRequest || status.Retryable() {
+		t.Fatalf("err = %v, want a non-retryable 400", err)
+	}
+}
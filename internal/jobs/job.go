@@ -0,0 +1,71 @@
+// Package jobs runs generations and comics asynchronously on an in-process worker pool
+// with a pluggable job state store.
+package jobs
+
+import (
+	"errors"
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/quota"
+)
+
+// Kind is what a job produces
+type Kind string
+
+const (
+	KindGeneration Kind = "generation"
+	KindComic      Kind = "comic"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Done reports whether the job has reached a final state
+func (s Status) Done() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrQueueFull   = errors.New("job queue is full")
+	ErrJobFinished = errors.New("job already finished")
+)
+
+type Job struct {
+	ID     string `json:"id"`
+	Kind   Kind   `json:"kind"`
+	Status Status `json:"status"`
+	Stage  string `json:"stage,omitempty"`
+	// Identity is the submitter, restored in the worker so services see
+	// the same caller as a synchronous request would
+	Identity domain.Identity `json:"identity"`
+	// Charge is the quota the submit request paid, refunded if the job
+	// fails or is cancelled
+	Charge       *quota.Charge          `json:"charge,omitempty"`
+	Request      services.TierRequest   `json:"request,omitempty"`
+	Result       *services.TierResult   `json:"result,omitempty"`
+	ComicRequest *services.ComicRequest `json:"comic_request,omitempty"`
+	ComicResult  *ai.ComicStory         `json:"comic_result,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	StartedAt    *time.Time             `json:"started_at,omitempty"`
+	FinishedAt   *time.Time             `json:"finished_at,omitempty"`
+}
+
+// Owner is the user the job belongs to
+func (j *Job) Owner() string {
+	if j.Identity.UserID != "" {
+		return j.Identity.UserID
+	}
+	return j.Request.UserID
+}
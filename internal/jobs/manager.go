@@ -0,0 +1,327 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/quota"
+)
+
+// Generator is the part of the service graph the workers need
+type Generator interface {
+	Generate(ctx context.Context, req services.TierRequest) (*services.TierResult, error)
+	GenerateComic(ctx context.Context, req services.ComicRequest) (*ai.ComicStory, error)
+}
+
+// Refunder gives back the quota a job was charged
+type Refunder interface {
+	RefundCharge(ctx context.Context, charge quota.Charge) error
+}
+
+type Config struct {
+	Workers    int
+	QueueDepth int
+	// CancelPoll is how often a running job checks the store for a
+	// cancel made on another node; 0 means every 2 seconds
+	CancelPoll time.Duration
+	// Quota refunds jobs that do not succeed; nil keeps every charge
+	Quota Refunder
+}
+
+// errNotQueued and errNotRunning abort a status change whose job has
+// moved on, e.g. been cancelled, since the caller last looked
+var (
+	errNotQueued  = errors.New("job is no longer queued")
+	errNotRunning = errors.New("job is no longer running")
+)
+
+var errNotStarted = errors.New("server shut down before the job started")
+
+// Manager accepts jobs into a bounded queue and runs them on a fixed pool
+// of workers. Every status change is a compare-and-set on the store, so a
+// cancel is never overwritten, and a node running a job stops it when the
+// job is cancelled through any node.
+type Manager struct {
+	store      Store
+	generator  Generator
+	queue      chan string
+	workers    int
+	cancelPoll time.Duration
+	quota      Refunder
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewManager(store Store, generator Generator, config Config) *Manager {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.QueueDepth < 1 {
+		config.QueueDepth = 1
+	}
+	if config.CancelPoll <= 0 {
+		config.CancelPoll = 2 * time.Second
+	}
+
+	return &Manager{
+		store:      store,
+		generator:  generator,
+		queue:      make(chan string, config.QueueDepth),
+		workers:    config.Workers,
+		cancelPoll: config.CancelPoll,
+		quota:      config.Quota,
+		running:    make(map[string]context.CancelFunc),
+	}
+}
+
+// Start launches the workers; they stop when ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.work(ctx)
+	}
+}
+
+// Wait blocks until every worker has stopped, then fails the jobs still
+// queued: the queue lives in this process, so no worker will run them
+func (m *Manager) Wait() {
+	m.wg.Wait()
+
+	for {
+		select {
+		case id := <-m.queue:
+			m.finish(context.Background(), id, StatusFailed, nil, nil, errNotStarted)
+		default:
+			return
+		}
+	}
+}
+
+// Submit queues a tier generation
+func (m *Manager) Submit(ctx context.Context, req services.TierRequest) (*Job, error) {
+	return m.submit(ctx, &Job{Kind: KindGeneration, Request: req})
+}
+
+// SubmitComic queues a full comic run
+func (m *Manager) SubmitComic(ctx context.Context, req services.ComicRequest) (*Job, error) {
+	return m.submit(ctx, &Job{Kind: KindComic, ComicRequest: &req})
+}
+
+// submit stores a queued job and hands it to the pool, or fails with
+// ErrQueueFull without keeping the job
+func (m *Manager) submit(ctx context.Context, job *Job) (*Job, error) {
+	job.ID = newJobID()
+	job.Status = StatusQueued
+	job.CreatedAt = time.Now().UTC()
+	job.Identity, _ = services.IdentityFrom(ctx)
+	// The Quota middleware gives a dry run's charge back itself
+	if charge, ok := quota.ChargeFrom(ctx); ok && !job.Request.DryRun {
+		job.Charge = &charge
+	}
+
+	if err := m.store.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	select {
+	case m.queue <- job.ID:
+		return job, nil
+	default:
+		m.store.Delete(ctx, job.ID)
+		return nil, ErrQueueFull
+	}
+}
+
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Cancel stops a queued or running job. The cancelled state is stored at
+// once; the node running the job, this one or another, then stops it.
+func (m *Manager) Cancel(ctx context.Context, id string) (*Job, error) {
+	job, err := m.store.Update(ctx, id, func(job *Job) error {
+		if job.Status.Done() {
+			return ErrJobFinished
+		}
+		finished := time.Now().UTC()
+		job.Status = StatusCancelled
+		job.FinishedAt = &finished
+		return nil
+	})
+	if errors.Is(err, ErrJobFinished) {
+		if job, err = m.store.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return job, ErrJobFinished
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.refund(ctx, job)
+
+	m.mu.Lock()
+	cancel, running := m.running[id]
+	m.mu.Unlock()
+	if running {
+		cancel()
+	}
+	return job, nil
+}
+
+// QueueDepth is the number of jobs waiting for a worker
+func (m *Manager) QueueDepth() int {
+	return len(m.queue)
+}
+
+func (m *Manager) work(ctx context.Context) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-m.queue:
+			// select picks at random when shutdown and a job arrive together
+			if ctx.Err() != nil {
+				m.finish(ctx, id, StatusFailed, nil, nil, errNotStarted)
+				return
+			}
+			m.run(ctx, id)
+		}
+	}
+}
+
+func (m *Manager) run(ctx context.Context, id string) {
+	started := time.Now().UTC()
+	job, err := m.store.Update(ctx, id, func(job *Job) error {
+		if job.Status != StatusQueued {
+			return errNotQueued
+		}
+		job.Status = StatusRunning
+		job.StartedAt = &started
+		return nil
+	})
+	if err != nil {
+		// Cancelled while queued, or gone
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m.mu.Lock()
+	m.running[id] = cancel
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.running, id)
+		m.mu.Unlock()
+	}()
+
+	go m.watchCancel(jobCtx, id, cancel)
+
+	// Keep the stored job's stage current so pollers see progress
+	jobCtx = services.WithProgress(jobCtx, func(event domain.ProgressEvent) {
+		m.store.Update(ctx, id, func(job *Job) error {
+			if job.Status != StatusRunning {
+				return errNotRunning
+			}
+			job.Stage = event.Stage
+			return nil
+		})
+	})
+	jobCtx = services.WithJobID(jobCtx, job.ID)
+	if job.Identity.UserID != "" {
+		jobCtx = services.WithIdentity(jobCtx, job.Identity)
+	}
+
+	var result *services.TierResult
+	var comic *ai.ComicStory
+	if job.Kind == KindComic {
+		comic, err = m.generator.GenerateComic(jobCtx, *job.ComicRequest)
+	} else {
+		result, err = m.generator.Generate(jobCtx, job.Request)
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		m.finish(ctx, id, StatusFailed, nil, nil, errors.New("interrupted by server shutdown"))
+	case errors.Is(jobCtx.Err(), context.Canceled):
+		m.finish(ctx, id, StatusCancelled, nil, nil, nil)
+	case err != nil:
+		m.finish(ctx, id, StatusFailed, nil, nil, err)
+	default:
+		m.finish(ctx, id, StatusSucceeded, result, comic, nil)
+	}
+}
+
+// watchCancel stops a running job once its stored state is final, which is
+// how a cancel made on another node reaches this one
+func (m *Manager) watchCancel(ctx context.Context, id string, cancel context.CancelFunc) {
+	ticker := time.NewTicker(m.cancelPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if job, err := m.store.Get(ctx, id); err == nil && job.Status.Done() {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// finish records the job's final state, unless it already has one: a
+// cancel stored while the job ran wins over the worker's outcome. A job
+// that did not succeed gets its quota charge back.
+func (m *Manager) finish(ctx context.Context, id string, status Status, result *services.TierResult, comic *ai.ComicStory, err error) {
+	finished := time.Now().UTC()
+
+	// Use a fresh context: the worker context may be the one being cancelled
+	ctx = context.WithoutCancel(ctx)
+	job, updateErr := m.store.Update(ctx, id, func(job *Job) error {
+		if job.Status.Done() {
+			return ErrJobFinished
+		}
+		job.Status = status
+		job.Result = result
+		job.ComicResult = comic
+		job.FinishedAt = &finished
+		if err != nil {
+			job.Error = err.Error()
+		}
+		return nil
+	})
+	if updateErr == nil && status != StatusSucceeded {
+		m.refund(ctx, job)
+	}
+}
+
+// refund gives back the charge of a job that just reached a final state
+// other than success. Only the status change that stored that state calls
+// it, so a job is refunded at most once.
+func (m *Manager) refund(ctx context.Context, job *Job) {
+	if m.quota == nil || job.Charge == nil {
+		return
+	}
+	m.quota.RefundCharge(context.WithoutCancel(ctx), *job.Charge)
+}
+
+func newJobID() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
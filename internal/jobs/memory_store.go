@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps jobs in process memory and forgets finished jobs
+// after the retention period
+type MemoryStore struct {
+	mu        sync.RWMutex
+	jobs      map[string]Job
+	retention time.Duration
+}
+
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		jobs:      make(map[string]Job),
+		retention: retention,
+	}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = *job
+	s.evictExpired()
+	return nil
+}
+
+// Get returns a copy so callers cannot race with the workers
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, id string, update func(job *Job) error) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	if err := update(&job); err != nil {
+		return nil, err
+	}
+	s.jobs[id] = job
+	return &job, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryStore) evictExpired() {
+	if s.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxUpdateAttempts bounds how often Update retries a transaction that
+// lost a race with another writer
+const maxUpdateAttempts = 10
+
+// RedisStore keeps jobs in Redis or any server speaking its protocol, so
+// job status survives restarts and is visible to every API node
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// NewRedisStore connects using a redis:// URL, e.g. redis://redis:6379/0
+func NewRedisStore(url string, ttl time.Duration) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisStore{
+		client:    redis.NewClient(options),
+		keyPrefix: "geminizer:job:",
+		ttl:       ttl,
+	}, nil
+}
+
+func (s *RedisStore) Save(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.keyPrefix+job.ID, data, s.ttl).Err()
+}
+
+func (s *RedisStore) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := s.client.Get(ctx, s.keyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update reads, changes and writes the job in a WATCH transaction, and
+// starts over when another node wrote the job in between
+func (s *RedisStore) Update(ctx context.Context, id string, update func(job *Job) error) (*Job, error) {
+	key := s.keyPrefix + id
+
+	var updated Job
+	transaction := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return ErrJobNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		if err := update(&job); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(&job); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, s.ttl)
+			return nil
+		})
+		updated = job
+		return err
+	}
+
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		err := s.client.Watch(ctx, transaction, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, errors.New("job update kept conflicting with other writers")
+}
+
+func (s *RedisStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, s.keyPrefix+id).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
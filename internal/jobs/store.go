@@ -0,0 +1,14 @@
+package jobs
+
+import "context"
+
+// Store persists job state. Implementations must be safe for concurrent use.
+type Store interface {
+	Save(ctx context.Context, job *Job) error
+	Get(ctx context.Context, id string) (*Job, error)
+	Delete(ctx context.Context, id string) error
+	// Update applies update to the stored job atomically and returns the
+	// result. An error from update leaves the job unchanged and is
+	// returned as is.
+	Update(ctx context.Context, id string, update func(job *Job) error) (*Job, error)
+}
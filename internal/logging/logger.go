@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// StdLogger writes key/value log lines through the standard library logger
+type StdLogger struct {
+	out *log.Logger
+}
+
+func NewStdLogger(w io.Writer) *StdLogger {
+	return &StdLogger{
+		out: log.New(w, "geminizer ", log.LstdFlags|log.LUTC),
+	}
+}
+
+func (l *StdLogger) Info(msg string, fields ...interface{}) {
+	l.out.Println("INFO " + msg + formatFields(fields))
+}
+
+func (l *StdLogger) Error(msg string, err error, fields ...interface{}) {
+	l.out.Println(fmt.Sprintf("ERROR %s: %v", msg, err) + formatFields(fields))
+}
+
+func formatFields(fields []interface{}) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(fields); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+	}
+	return b.String()
+}
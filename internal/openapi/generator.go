@@ -0,0 +1,258 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// Generator turns Go types into component schemas. Struct fields follow
+// encoding/json naming; constraints come from the validate tag:
+//
+//	Prompt string `json:"prompt" validate:"required,min=1,max=4000"`
+//	Style  string `json:"style" validate:"enum=style"`
+//
+// where enum names are resolved through the enums passed to NewGenerator.
+// On a slice, enum constrains each element.
+// A Generator may be read concurrently (Resolve, Validate, Schema of an
+// already registered type) once every type has been added.
+type Generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	enums   map[string][]string
+}
+
+func NewGenerator(enums map[string][]string) *Generator {
+	return &Generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		enums:   enums,
+	}
+}
+
+// Schema returns a schema for the type of v. Structs are registered as
+// components and referenced.
+func (g *Generator) Schema(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	return g.schemaFor(reflect.TypeOf(v))
+}
+
+// Object documents an ad-hoc JSON object from example values, for
+// handlers that respond with gin.H
+func (g *Generator) Object(fields map[string]interface{}) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for name, value := range fields {
+		schema.Properties[name] = g.Schema(value)
+	}
+	return schema
+}
+
+// Component returns a registered schema by name, for adjustments that
+// cannot be expressed with tags (e.g. fields of types we do not own)
+func (g *Generator) Component(name string) (*Schema, bool) {
+	schema, ok := g.schemas[name]
+	return schema, ok
+}
+
+// Components returns every schema registered so far
+func (g *Generator) Components() map[string]*Schema {
+	return g.schemas
+}
+
+// Resolve follows a $ref to its component
+func (g *Generator) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = g.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func (g *Generator) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		schema := g.schemaFor(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "duration in nanoseconds"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+
+	// interface{} and anything else: any JSON value
+	return &Schema{}
+}
+
+func (g *Generator) structRef(t reflect.Type) *Schema {
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := g.componentName(t)
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	// Register before walking the fields so recursive types terminate
+	g.names[t] = name
+	g.schemas[name] = schema
+	g.addFields(schema, t)
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName is the type name, prefixed with its package when two
+// packages define the same name
+func (g *Generator) componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		name = "Anonymous"
+	}
+	if _, taken := g.schemas[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	candidate := strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	for i := 2; ; i++ {
+		if _, taken := g.schemas[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%s%d", strings.ToUpper(pkg[:1])+pkg[1:], name, i)
+	}
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Embedded structs are flattened, as encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaFor(field.Type)
+		if g.applyConstraints(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyConstraints adds validate tag rules to a property and reports
+// whether it is required
+func (g *Generator) applyConstraints(property *Schema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch key {
+		case "required":
+			required = true
+		case "enum":
+			if property.Type == "array" && property.Items != nil {
+				property.Items.Enum = g.enums[value]
+			} else {
+				property.Enum = g.enums[value]
+			}
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			setBound(property, key, n)
+		}
+	}
+	return required
+}
+
+func setBound(property *Schema, key string, n int) {
+	switch property.Type {
+	case "string":
+		if key == "min" {
+			property.MinLength = &n
+		} else {
+			property.MaxLength = &n
+		}
+	case "array":
+		if key == "min" {
+			property.MinItems = &n
+		} else {
+			property.MaxItems = &n
+		}
+	case "integer", "number":
+		f := float64(n)
+		if key == "min" {
+			property.Minimum = &f
+		} else {
+			property.Maximum = &f
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError is one problem with one field of a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validate checks a JSON document against schema and returns every
+// violation, in field order. A body that is not JSON at all is reported
+// as a single error on the root.
+func (g *Generator) Validate(schema *Schema, data []byte) []FieldError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []FieldError{{Field: "", Code: "invalid_json", Message: err.Error()}}
+	}
+
+	v := &validator{generator: g}
+	v.validate("", schema, value)
+	return v.errors
+}
+
+type validator struct {
+	generator *Generator
+	errors    []FieldError
+}
+
+func (v *validator) fail(path string, code string, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Field: path, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(path string, schema *Schema, value interface{}) {
+	schema = v.generator.Resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	if value == nil {
+		if schema.Type != "" && !schema.Nullable {
+			v.fail(path, "type", "must not be null")
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(path, "type", "must be an object")
+			return
+		}
+		v.validateObject(path, schema, object)
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.fail(path, "type", "must be an array")
+			return
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			v.fail(path, "min_items", "must contain at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			v.fail(path, "max_items", "must contain at most %d items", *schema.MaxItems)
+		}
+		for i, item := range items {
+			v.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item)
+		}
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.fail(path, "type", "must be a string")
+			return
+		}
+		v.validateString(path, schema, s)
+
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.fail(path, "type", "must be a number")
+			return
+		}
+		v.validateNumber(path, schema, n)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "type", "must be a boolean")
+		}
+	}
+}
+
+func (v *validator) validateObject(path string, schema *Schema, object map[string]interface{}) {
+	// encoding/json matches keys case-insensitively, so the validator does too
+	present := make(map[string]string, len(object))
+	for key := range object {
+		present[strings.ToLower(key)] = key
+	}
+
+	for _, name := range schema.Required {
+		// A null value is reported by the type check below
+		if _, ok := present[strings.ToLower(name)]; !ok {
+			v.fail(join(path, name), "required", "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property := lookupProperty(schema.Properties, key)
+		if property == nil {
+			if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+				v.validate(join(path, key), additional, object[key])
+			} else if schema.AdditionalProperties == false {
+				v.fail(join(path, key), "unknown_field", "is not a known field")
+			}
+			continue
+		}
+		v.validate(join(path, key), property, object[key])
+	}
+}
+
+func (v *validator) validateString(path string, schema *Schema, s string) {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(path, "min_length", "must be at least %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(path, "max_length", "must be at most %d characters", *schema.MaxLength)
+	}
+
+	// An empty optional string means "not set", as with omitempty
+	if len(schema.Enum) > 0 && s != "" {
+		for _, allowed := range schema.Enum {
+			if s == allowed {
+				return
+			}
+		}
+		v.fail(path, "enum", "must be one of: %s", strings.Join(schema.Enum, ", "))
+	}
+}
+
+func (v *validator) validateNumber(path string, schema *Schema, n json.Number) {
+	if schema.Type == "integer" {
+		if _, err := n.Int64(); err != nil {
+			v.fail(path, "type", "must be an integer")
+			return
+		}
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		v.fail(path, "type", "must be a number")
+		return
+	}
+	if schema.Minimum != nil && f < *schema.Minimum {
+		v.fail(path, "minimum", "must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		v.fail(path, "maximum", "must be at most %v", *schema.Maximum)
+	}
+}
+
+func lookupProperty(properties map[string]*Schema, key string) *Schema {
+	if property, ok := properties[key]; ok {
+		return property
+	}
+	for name, property := range properties {
+		if strings.EqualFold(name, key) {
+			return property
+		}
+	}
+	return nil
+}
+
+func join(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,277 @@
+// Package profiles loads the pipeline configuration: agent thresholds and
+// defaults, and the named profiles a request can select. It is read once
+// at startup, so tuning needs a restart but not a rebuild.
+package profiles
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+	"geminizer-enterprise/internal/core/services"
+)
+
+//go:embed builtin.yaml
+var builtin embed.FS
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Config is the whole file. Every threshold is optional; an omitted one
+// keeps the builtin value.
+type Config struct {
+	Agents   Agents             `json:"agents"`
+	Health   Health             `json:"health"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Agents tunes the agents every tier and profile shares
+type Agents struct {
+	services.Tuning
+	// LoopSimilarity is how alike two comic panels may be before the
+	// safety filter reports a loop (builtin 0.8)
+	LoopSimilarity float64 `json:"loop_similarity,omitempty"`
+}
+
+// Health overrides the health monitor's thresholds
+type Health struct {
+	MemoryUsage   float64  `json:"memory_usage,omitempty"`
+	CPULoad       float64  `json:"cpu_load,omitempty"`
+	ResponseTime  Duration `json:"response_time,omitempty"`
+	ErrorRate     float64  `json:"error_rate,omitempty"`
+	UptimeMinimum Duration `json:"uptime_minimum,omitempty"`
+}
+
+// Profile is one named pipeline; its name is its key in the file
+type Profile struct {
+	Description string                   `json:"description,omitempty"`
+	Stages      []string                 `json:"stages"`
+	Tuning      services.Tuning          `json:"tuning"`
+	Defaults    services.ProfileDefaults `json:"defaults"`
+}
+
+// Duration is written as a Go duration string, e.g. "2s" or "5m"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("durations are strings such as \"2s\"")
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Builtin is the configuration shipped in the binary
+func Builtin() (*Config, error) {
+	data, err := builtin.ReadFile("builtin.yaml")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, ".yaml")
+}
+
+// Load reads a .yaml, .yml or .json file, replacing the builtin
+// configuration
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline config: %v", err)
+	}
+	config, err := Parse(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("pipeline config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Parse decodes and validates a configuration. YAML is converted to JSON
+// first so both formats share one schema and reject unknown keys alike.
+func Parse(data []byte, ext string) (*Config, error) {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		converted, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	case ".json":
+	default:
+		return nil, fmt.Errorf("unsupported format %q: use .yaml, .yml or .json", ext)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var config Config
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	if err := fraction("agents.style_threshold", c.Agents.StyleThreshold); err != nil {
+		return err
+	}
+	if err := fraction("agents.loop_similarity", c.Agents.LoopSimilarity); err != nil {
+		return err
+	}
+
+	// Memory and CPU penalties divide by the headroom above the threshold
+	for field, value := range map[string]float64{
+		"health.memory_usage": c.Health.MemoryUsage,
+		"health.cpu_load":     c.Health.CPULoad,
+		"health.error_rate":   c.Health.ErrorRate,
+	} {
+		if value < 0 || value >= 1 {
+			return fmt.Errorf("%s must be at least 0 and below 1, got %v", field, value)
+		}
+	}
+	if c.Health.ResponseTime < 0 || c.Health.UptimeMinimum < 0 {
+		return fmt.Errorf("health durations must not be negative")
+	}
+
+	styles := ai.NewArtStyleEngine()
+	if err := knownValue("agents.default_style", c.Agents.DefaultStyle, styles.Styles()); err != nil {
+		return err
+	}
+
+	for _, profile := range c.profiles() {
+		if !validName.MatchString(profile.Name) {
+			return fmt.Errorf("profile %q: use lowercase letters, digits, - and _", profile.Name)
+		}
+		if err := c.Profiles[profile.Name].validate(styles); err != nil {
+			return fmt.Errorf("profile %q: %v", profile.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p Profile) validate(styles *ai.ArtStyleEngine) error {
+	if err := services.ValidateStages(p.Stages); err != nil {
+		return err
+	}
+	if err := fraction("tuning.style_threshold", p.Tuning.StyleThreshold); err != nil {
+		return err
+	}
+
+	for field, check := range map[string]struct {
+		value   string
+		allowed []string
+	}{
+		"tuning.default_style": {p.Tuning.DefaultStyle, styles.Styles()},
+		"defaults.style":       {p.Defaults.Style, styles.Styles()},
+		"defaults.filter":      {p.Defaults.Filter, styles.Filters()},
+		"defaults.shot_type":   {p.Defaults.ShotType, ai.ShotTypes},
+		"defaults.mood":        {p.Defaults.Mood, ai.Moods},
+	} {
+		if err := knownValue(field, check.value, check.allowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fraction accepts 0, meaning unset, or a value in (0, 1]
+func fraction(field string, value float64) error {
+	if value < 0 || value > 1 {
+		return fmt.Errorf("%s must be between 0 and 1, got %v", field, value)
+	}
+	return nil
+}
+
+func knownValue(field string, value string, allowed []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range allowed {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %s %q", field, value)
+}
+
+// Apply tunes the graph's agents and installs the profiles
+func (c *Config) Apply(graph *services.ServiceGraph) error {
+	if err := graph.Tune(c.Agents.Tuning); err != nil {
+		return fmt.Errorf("pipeline config: %v", err)
+	}
+	if c.Agents.LoopSimilarity != 0 {
+		if err := graph.Comics.SetLoopSimilarity(c.Agents.LoopSimilarity); err != nil {
+			return fmt.Errorf("pipeline config: %v", err)
+		}
+	}
+	if err := graph.SetProfiles(c.profiles()); err != nil {
+		return fmt.Errorf("pipeline config: %v", err)
+	}
+	return nil
+}
+
+// HealthThresholds overlays the configured thresholds on the defaults
+func (c *Config) HealthThresholds() management.HealthThresholds {
+	thresholds := management.DefaultHealthThresholds()
+	if c.Health.MemoryUsage != 0 {
+		thresholds.MemoryUsage = c.Health.MemoryUsage
+	}
+	if c.Health.CPULoad != 0 {
+		thresholds.CPULoad = c.Health.CPULoad
+	}
+	if c.Health.ResponseTime != 0 {
+		thresholds.ResponseTime = time.Duration(c.Health.ResponseTime)
+	}
+	if c.Health.ErrorRate != 0 {
+		thresholds.ErrorRate = c.Health.ErrorRate
+	}
+	if c.Health.UptimeMinimum != 0 {
+		thresholds.UptimeMinimum = time.Duration(c.Health.UptimeMinimum)
+	}
+	return thresholds
+}
+
+// profiles lists the profiles in name order, so errors are reproducible
+func (c *Config) profiles() []services.Profile {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	profiles := make([]services.Profile, len(names))
+	for i, name := range names {
+		p := c.Profiles[name]
+		profiles[i] = services.Profile{
+			Name:        name,
+			Description: p.Description,
+			Stages:      p.Stages,
+			Tuning:      p.Tuning,
+			Defaults:    p.Defaults,
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,31 @@
+package quota
+
+import (
+	"context"
+	"time"
+)
+
+// Charge is what Consume took for a request whose generation runs after
+// the response, such as a job, so the work can give it back if it fails
+type Charge struct {
+	Subject   Subject   `json:"subject"`
+	Units     int       `json:"units"`
+	ChargedAt time.Time `json:"charged_at"`
+}
+
+type chargeKey struct{}
+
+// WithCharge hands the request's charge to whatever takes over its work
+func WithCharge(ctx context.Context, charge Charge) context.Context {
+	return context.WithValue(ctx, chargeKey{}, charge)
+}
+
+func ChargeFrom(ctx context.Context) (Charge, bool) {
+	charge, ok := ctx.Value(chargeKey{}).(Charge)
+	return charge, ok
+}
+
+// RefundCharge gives back a Charge
+func (l *Limiter) RefundCharge(ctx context.Context, charge Charge) error {
+	return l.Refund(ctx, charge.Subject, charge.Units, charge.ChargedAt)
+}
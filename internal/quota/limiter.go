@@ -0,0 +1,224 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+var ErrQuotaExceeded = errors.New("generation quota exceeded")
+
+// RateDecision is the most restrictive bucket a request was checked against
+type RateDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+// Usage describes the quota window a generation was refused by
+type Usage struct {
+	Scope   string    `json:"scope"`  // user or tenant
+	Window  string    `json:"window"` // daily or monthly
+	Limit   int       `json:"limit"`
+	Used    int       `json:"used"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// Limiter applies plan limits to users and their tenants
+type Limiter struct {
+	store       Store
+	plans       map[string]Plan
+	defaultPlan string
+	now         func() time.Time
+}
+
+func NewLimiter(store Store, plans map[string]Plan, defaultPlan string) (*Limiter, error) {
+	if _, err := LookupPlan(plans, defaultPlan); err != nil {
+		return nil, fmt.Errorf("default plan: %v", err)
+	}
+
+	return &Limiter{
+		store:       store,
+		plans:       plans,
+		defaultPlan: defaultPlan,
+		now:         time.Now,
+	}, nil
+}
+
+// Plan resolves the subject's plan, falling back to the default for
+// callers whose token names no plan or one we no longer sell
+func (l *Limiter) Plan(subject Subject) Plan {
+	if plan, ok := l.plans[subject.Plan]; ok {
+		return plan
+	}
+	return l.plans[l.defaultPlan]
+}
+
+// Allow takes a token from the user's bucket and the tenant's. Every
+// bucket is checked before any is spent, so a request the tenant bucket
+// refuses does not cost the user a token.
+func (l *Limiter) Allow(ctx context.Context, subject Subject) (RateDecision, error) {
+	plan := l.Plan(subject)
+	now := l.now()
+
+	type bucket struct {
+		key       string
+		perMinute int
+		burst     int
+	}
+	var buckets []bucket
+	for _, scope := range scopes(subject, plan) {
+		if scope.limits.RequestsPerMinute <= 0 {
+			continue
+		}
+
+		burst := scope.limits.Burst
+		if burst <= 0 {
+			burst = scope.limits.RequestsPerMinute
+		}
+		buckets = append(buckets, bucket{"rate:" + scope.key, scope.limits.RequestsPerMinute, burst})
+	}
+
+	for _, b := range buckets {
+		state, err := l.store.PeekToken(ctx, b.key, b.perMinute, b.burst, now)
+		if err != nil {
+			return RateDecision{}, err
+		}
+		if !state.Allowed {
+			return RateDecision{Limit: b.perMinute, Remaining: state.Remaining, Reset: state.Reset}, nil
+		}
+	}
+
+	decision := RateDecision{Allowed: true}
+	for _, b := range buckets {
+		state, err := l.store.TakeToken(ctx, b.key, b.perMinute, b.burst, now)
+		if err != nil {
+			return RateDecision{}, err
+		}
+
+		if decision.Limit == 0 || state.Remaining < decision.Remaining || !state.Allowed {
+			decision = RateDecision{
+				Allowed:   state.Allowed,
+				Limit:     b.perMinute,
+				Remaining: state.Remaining,
+				Reset:     state.Reset,
+			}
+		}
+		if !state.Allowed {
+			return decision, nil
+		}
+	}
+
+	return decision, nil
+}
+
+// Consume charges n generations against every daily and monthly quota of
+// the subject. Either all counters are charged or none are; on refusal the
+// error is a QUOTA_EXCEEDED AppError and the usage names the limit hit.
+func (l *Limiter) Consume(ctx context.Context, subject Subject, n int) (*Usage, error) {
+	plan := l.Plan(subject)
+	now := l.now().UTC()
+
+	type charge struct {
+		key       string
+		expiresAt time.Time
+	}
+	var charged []charge
+
+	rollback := func() {
+		for _, c := range charged {
+			l.store.IncrBy(context.WithoutCancel(ctx), c.key, -n, c.expiresAt)
+		}
+	}
+
+	for _, scope := range scopes(subject, plan) {
+		for _, window := range windows(scope.limits, now) {
+			if window.limit <= 0 {
+				continue
+			}
+
+			key := "count:" + scope.key + ":" + window.id
+			used, err := l.store.IncrBy(ctx, key, n, window.resetAt)
+			if err != nil {
+				rollback()
+				return nil, err
+			}
+			charged = append(charged, charge{key, window.resetAt})
+
+			if used > window.limit {
+				rollback()
+				usage := &Usage{
+					Scope:   scope.name,
+					Window:  window.name,
+					Limit:   window.limit,
+					Used:    used - n,
+					ResetAt: window.resetAt,
+				}
+				message := fmt.Sprintf("%s %s quota of %d generations reached", scope.name, window.name, window.limit)
+				return usage, domain.NewAppError(ErrQuotaExceeded, message, domain.ErrCodeQuotaExceeded)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Refund gives back n generations that Consume charged at chargedAt, for
+// requests that failed. Windows that have reset since keep their charge.
+func (l *Limiter) Refund(ctx context.Context, subject Subject, n int, chargedAt time.Time) error {
+	plan := l.Plan(subject)
+	now := l.now().UTC()
+
+	for _, scope := range scopes(subject, plan) {
+		for _, window := range windows(scope.limits, chargedAt.UTC()) {
+			if window.limit <= 0 || !now.Before(window.resetAt) {
+				continue
+			}
+
+			key := "count:" + scope.key + ":" + window.id
+			if _, err := l.store.IncrBy(context.WithoutCancel(ctx), key, -n, window.resetAt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type scope struct {
+	name   string
+	key    string
+	limits Limits
+}
+
+func scopes(subject Subject, plan Plan) []scope {
+	var result []scope
+	if subject.UserID != "" {
+		result = append(result, scope{"user", "user:" + subject.UserID, plan.User})
+	}
+	if subject.TenantID != "" {
+		result = append(result, scope{"tenant", "tenant:" + subject.TenantID, plan.Tenant})
+	}
+	return result
+}
+
+type window struct {
+	name    string
+	id      string
+	limit   int
+	resetAt time.Time
+}
+
+// windows are calendar days and months in UTC
+func windows(limits Limits, now time.Time) []window {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return []window{
+		{"daily", "day:" + day.Format("2006-01-02"), limits.DailyGenerations, day.AddDate(0, 0, 1)},
+		{"monthly", "month:" + month.Format("2006-01"), limits.MonthlyGenerations, month.AddDate(0, 1, 0)},
+	}
+}
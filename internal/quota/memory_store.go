@@ -0,0 +1,132 @@
+package quota
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket has refilled; a new bucket starts full, so
+	// one left idle until then can be forgotten
+	full time.Time
+}
+
+type counter struct {
+	value     int
+	expiresAt time.Time
+}
+
+// MemoryStore keeps limits per process. Each API node enforces its own
+// limits, so use the Redis store when running more than one.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	counters map[string]*counter
+	// swept is when idle buckets were last dropped
+	swept time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		counters: make(map[string]*counter),
+	}
+}
+
+func (s *MemoryStore) TakeToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error) {
+	return s.token(key, perMinute, burst, now, true), nil
+}
+
+func (s *MemoryStore) PeekToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error) {
+	return s.token(key, perMinute, burst, now, false), nil
+}
+
+// token refills the bucket at key and, when spend is set, takes a token
+func (s *MemoryStore) token(key string, perMinute int, burst int, now time.Time, spend bool) BucketState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rate := float64(perMinute) / 60
+	b, ok := s.buckets[key]
+	if !ok {
+		s.evictIdle(now)
+		b = &bucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	allowed := b.tokens >= 1
+	if allowed && spend {
+		b.tokens--
+	}
+	b.full = now.Add(secondsToDuration((float64(burst) - b.tokens) / rate))
+	return bucketState(allowed, b.tokens, rate, burst)
+}
+
+// evictIdle drops buckets that have refilled, at most once a minute;
+// they are the same as the full bucket a new caller starts with
+func (s *MemoryStore) evictIdle(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (s *MemoryStore) IncrBy(ctx context.Context, key string, delta int, expiresAt time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.expiresAt) {
+		c = &counter{expiresAt: expiresAt}
+		s.counters[key] = c
+		s.evictExpired()
+	}
+
+	c.value += delta
+	return c.value, nil
+}
+
+// evictExpired drops counters from past windows; called only when a new
+// counter is created, which happens at most a few times per user per day
+func (s *MemoryStore) evictExpired() {
+	now := time.Now()
+	for key, c := range s.counters {
+		if !now.Before(c.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+}
+
+// bucketState reports a bucket holding tokens after a take
+func bucketState(allowed bool, tokens float64, rate float64, burst int) BucketState {
+	state := BucketState{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+	}
+	if allowed {
+		state.Reset = secondsToDuration((float64(burst) - tokens) / rate)
+	} else {
+		state.Reset = secondsToDuration((1 - tokens) / rate)
+	}
+	return state
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds)) * time.Second
+}
@@ -0,0 +1,54 @@
+package quota
+
+import "fmt"
+
+// Limits bound one user or one tenant. A zero field means unlimited.
+type Limits struct {
+	RequestsPerMinute  int `json:"requests_per_minute"`
+	Burst              int `json:"burst"`
+	DailyGenerations   int `json:"daily_generations"`
+	MonthlyGenerations int `json:"monthly_generations"`
+}
+
+// Plan is a commercial tier: limits for each user and for the tenant as a
+// whole
+type Plan struct {
+	Name   string `json:"name"`
+	User   Limits `json:"user"`
+	Tenant Limits `json:"tenant"`
+}
+
+// DefaultPlans are the plan tiers sold today
+var DefaultPlans = map[string]Plan{
+	"free": {
+		Name:   "free",
+		User:   Limits{RequestsPerMinute: 30, Burst: 10, DailyGenerations: 20, MonthlyGenerations: 200},
+		Tenant: Limits{RequestsPerMinute: 60, Burst: 20, DailyGenerations: 50, MonthlyGenerations: 500},
+	},
+	"pro": {
+		Name:   "pro",
+		User:   Limits{RequestsPerMinute: 120, Burst: 30, DailyGenerations: 500, MonthlyGenerations: 10000},
+		Tenant: Limits{RequestsPerMinute: 600, Burst: 100, DailyGenerations: 2000, MonthlyGenerations: 40000},
+	},
+	"enterprise": {
+		Name:   "enterprise",
+		User:   Limits{RequestsPerMinute: 600, Burst: 120, DailyGenerations: 5000},
+		Tenant: Limits{RequestsPerMinute: 6000, Burst: 1000, DailyGenerations: 100000},
+	},
+}
+
+// Subject is who a request is charged to
+type Subject struct {
+	UserID   string `json:"user_id,omitempty"`
+	TenantID string `json:"tenant_id,omitempty"`
+	Plan     string `json:"plan,omitempty"`
+}
+
+// LookupPlan returns the named plan, or an error for unknown names
+func LookupPlan(plans map[string]Plan, name string) (Plan, error) {
+	plan, ok := plans[name]
+	if !ok {
+		return Plan{}, fmt.Errorf("unknown plan %q", name)
+	}
+	return plan, nil
+}
@@ -0,0 +1,90 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeTokenScript refills a bucket stored as a hash and, when ARGV[4] is
+// 1, spends a token, in one round trip. It returns {allowed, tokens*1000}.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local spend = tonumber(ARGV[4]) == 1
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	allowed = 1
+end
+if not spend then
+	return {allowed, math.floor(tokens * 1000)}
+end
+
+if allowed == 1 then
+	tokens = tokens - 1
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, math.floor(tokens * 1000)}
+`)
+
+// RedisStore shares limits between API nodes through Redis or any server
+// speaking its protocol
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisStore connects using a redis:// URL, e.g. redis://redis:6379/0
+func NewRedisStore(url string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisStore{
+		client:    redis.NewClient(options),
+		keyPrefix: "geminizer:quota:",
+	}, nil
+}
+
+func (s *RedisStore) TakeToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error) {
+	return s.token(ctx, key, perMinute, burst, now, 1)
+}
+
+func (s *RedisStore) PeekToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error) {
+	return s.token(ctx, key, perMinute, burst, now, 0)
+}
+
+func (s *RedisStore) token(ctx context.Context, key string, perMinute int, burst int, now time.Time, spend int) (BucketState, error) {
+	rate := float64(perMinute) / 60
+	values, err := takeTokenScript.Run(ctx, s.client, []string{s.keyPrefix + key}, rate, burst, now.UnixMilli(), spend).Int64Slice()
+	if err != nil {
+		return BucketState{}, err
+	}
+
+	return bucketState(values[0] == 1, float64(values[1])/1000, rate, burst), nil
+}
+
+func (s *RedisStore) IncrBy(ctx context.Context, key string, delta int, expiresAt time.Time) (int, error) {
+	pipe := s.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, s.keyPrefix+key, int64(delta))
+	pipe.ExpireAt(ctx, s.keyPrefix+key, expiresAt)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,26 @@
+package quota
+
+import (
+	"context"
+	"time"
+)
+
+// BucketState is the outcome of taking a token from a bucket
+type BucketState struct {
+	Allowed   bool
+	Remaining int
+	// Reset is how long until the bucket is full again, or until the next
+	// token when the request was refused
+	Reset time.Duration
+}
+
+// Store holds token buckets and generation counters. Implementations must
+// make each call atomic for a single key.
+type Store interface {
+	TakeToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error)
+	// PeekToken reports what TakeToken would, without spending the token
+	PeekToken(ctx context.Context, key string, perMinute int, burst int, now time.Time) (BucketState, error)
+	// IncrBy adds delta to the counter at key, which expires at expiresAt,
+	// and returns the new value
+	IncrBy(ctx context.Context, key string, delta int, expiresAt time.Time) (int, error)
+}
@@ -0,0 +1,70 @@
+package local
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+var csvHeader = []string{
+	"id", "user_id", "tenant_id", "tier", "created_at", "intent", "style",
+	"quality_score", "image_url", "prompt", "final_prompt",
+}
+
+// WriteJSONL writes one JSON record per line
+func WriteJSONL(w io.Writer, records []*domain.GenerationRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes records with a header row
+func WriteCSV(w io.Writer, records []*domain.GenerationRecord) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := []string{
+			record.ID,
+			record.UserID,
+			record.TenantID,
+			record.Tier,
+			record.CreatedAt.UTC().Format(time.RFC3339),
+			record.Intent,
+			record.Style,
+			strconv.FormatFloat(record.QualityScore, 'f', 3, 64),
+			record.ImageURL,
+			record.Prompt,
+			record.FinalPrompt,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Export writes records in the named format ("jsonl" or "csv")
+func Export(w io.Writer, format string, records []*domain.GenerationRecord) error {
+	switch format {
+	case "jsonl":
+		return WriteJSONL(w, records)
+	case "csv":
+		return WriteCSV(w, records)
+	}
+	return fmt.Errorf("unsupported export format %q (expected jsonl or csv)", format)
+}
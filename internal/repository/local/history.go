@@ -0,0 +1,205 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// HistoryStore is an embedded, file-backed history repository. Records are
+// kept in memory and appended to a JSON-lines file, so the store works on
+// a laptop or a single node without Firestore.
+type HistoryStore struct {
+	mu      sync.RWMutex
+	path    string
+	records []*domain.GenerationRecord
+}
+
+// OpenHistoryStore loads the store at path, creating it if needed
+func OpenHistoryStore(path string) (*HistoryStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create history directory: %v", err)
+	}
+
+	store := &HistoryStore{
+		path:    path,
+		records: make([]*domain.GenerationRecord, 0),
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *HistoryStore) load() error {
+	records, err := readRecords(s.path)
+	if err != nil {
+		return err
+	}
+	s.records = records
+	return nil
+}
+
+// readRecords reads the JSON-lines file at path; a missing file is empty
+func readRecords(path string) ([]*domain.GenerationRecord, error) {
+	records := make([]*domain.GenerationRecord, 0)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record domain.GenerationRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("history line %d: %v", line, err)
+		}
+		records = append(records, &record)
+	}
+
+	return records, scanner.Err()
+}
+
+func (s *HistoryStore) SaveGeneration(ctx context.Context, record *domain.GenerationRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open history: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("append history: %v", err)
+	}
+
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *HistoryStore) GetUserHistory(ctx context.Context, userID string, limit int) ([]*domain.GenerationRecord, error) {
+	return s.Search(ctx, domain.HistoryFilter{UserID: userID, Limit: limit})
+}
+
+// Search returns matching records, newest first
+func (s *HistoryStore) Search(ctx context.Context, filter domain.HistoryFilter) ([]*domain.GenerationRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []*domain.GenerationRecord
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if !filter.Matches(s.records[i]) {
+			continue
+		}
+		results = append(results, s.records[i])
+		if filter.Limit > 0 && len(results) == filter.Limit {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+func (s *HistoryStore) Get(ctx context.Context, id string) (*domain.GenerationRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, record := range s.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+
+	return nil, fmt.Errorf("history record not found: %s", id)
+}
+
+// Delete removes a record and rewrites the backing file. Another process
+// (the CLI, or a second server) may have appended since this store loaded,
+// so the file is re-read under its lock rather than rewritten from memory.
+func (s *HistoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	records, err := readRecords(s.path)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]*domain.GenerationRecord, 0, len(records))
+	for _, record := range records {
+		if record.ID != id {
+			kept = append(kept, record)
+		}
+	}
+
+	if len(kept) == len(records) {
+		s.records = records
+		return fmt.Errorf("history record not found: %s", id)
+	}
+
+	if err := s.rewrite(kept); err != nil {
+		return err
+	}
+
+	s.records = kept
+	return nil
+}
+
+// rewrite replaces the file atomically so a crash never leaves it truncated
+func (s *HistoryStore) rewrite(records []*domain.GenerationRecord) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".history-*")
+	if err != nil {
+		return fmt.Errorf("rewrite history: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteJSONL(tmp, records); err != nil {
+		tmp.Close()
+		return fmt.Errorf("rewrite history: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("rewrite history: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
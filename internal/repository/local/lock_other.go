@@ -0,0 +1,9 @@
+//go:build !unix
+
+package local
+
+// lockFile is a no-op where flock is unavailable; the store's mutex still
+// serialises writers within one process
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
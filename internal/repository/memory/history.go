@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+// HistoryRepository keeps generation history in process memory
+type HistoryRepository struct {
+	mu      sync.RWMutex
+	records []*domain.GenerationRecord
+}
+
+func NewHistoryRepository() *HistoryRepository {
+	return &HistoryRepository{
+		records: make([]*domain.GenerationRecord, 0),
+	}
+}
+
+func (r *HistoryRepository) SaveGeneration(ctx context.Context, record *domain.GenerationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+	return nil
+}
+
+// GetUserHistory returns the newest records for a user first
+func (r *HistoryRepository) GetUserHistory(ctx context.Context, userID string, limit int) ([]*domain.GenerationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var history []*domain.GenerationRecord
+	for i := len(r.records) - 1; i >= 0; i-- {
+		if r.records[i].UserID != userID {
+			continue
+		}
+		history = append(history, r.records[i])
+		if limit > 0 && len(history) == limit {
+			break
+		}
+	}
+
+	return history, nil
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config is read from the environment, matching docker-compose
+type Config struct {
+	Port          string
+	GRPCPort      string
+	Env           string
+	JWTSecret     string
+	JWTPublicKey  string // path to a PEM RSA key for RS256 tokens
+	JWTIssuer     string
+	AuthDisabled  bool // runs every request as a local admin; development only
+	HistoryPath   string
+	ModerationLog string
+	TenantFile    string // persists tenants and user assignments when set
+
+	// Asynchronous jobs
+	JobWorkers    int
+	JobQueueDepth int
+	JobStore      string // memory or redis
+	JobTTL        time.Duration
+	RedisURL      string
+
+	// Rate limits and quotas
+	LimitStore  string // memory or redis
+	DefaultPlan string
+
+	// Idempotency-Key replay
+	IdempotencyStore string // memory or redis
+	IdempotencyTTL   time.Duration
+
+	// Webhooks
+	WebhookSecret      string
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+	WebhookAllowLocal  bool // deliver to loopback and private addresses; development only
+
+	// Demo scenarios
+	DemoScenarioDir string // replaces the builtin catalog when set
+	DemoCacheTTL    time.Duration
+
+	// Agent tuning and pipeline profiles
+	PipelineConfig string // replaces the builtin configuration when set
+
+	// Image providers; each is registered when its URL or key is set
+	ImageProvider    string // default provider; empty keeps the builtin generator
+	ImageTimeout     time.Duration
+	GeminiAPIKey     string
+	GeminiBaseURL    string
+	GeminiImageModel string
+	A1111BaseURL     string
+	OpenAIAPIKey     string
+	OpenAIBaseURL    string
+	OpenAIImageModel string
+
+	// Failover across image providers
+	ImageMaxAttempts    int     // per image, across providers
+	ImageRetryBudget    float64 // retries earned per image
+	BreakerFailures     int     // consecutive failures that open a breaker
+	BreakerOpenDuration time.Duration
+
+	// Batch generation
+	BatchConcurrency int
+	BatchMaxItems    int
+
+	// Live feed
+	FeedInterval    time.Duration
+	FeedBuffer      int
+	FeedSlowTimeout time.Duration
+}
+
+// LoadConfig reads the environment. A variable that is set but does not
+// parse is an error naming it, rather than a silent fallback to the default.
+func LoadConfig() (Config, error) {
+	var env envReader
+	config := Config{
+		Port:          getEnv("PORT", "8080"),
+		GRPCPort:      getEnv("GRPC_PORT", "9090"),
+		Env:           getEnv("ENV", "development"),
+		JWTSecret:     os.Getenv("JWT_SECRET"),
+		JWTPublicKey:  os.Getenv("JWT_PUBLIC_KEY_FILE"),
+		JWTIssuer:     os.Getenv("JWT_ISSUER"),
+		AuthDisabled:  env.bool("AUTH_DISABLED", false),
+		HistoryPath:   os.Getenv("HISTORY_PATH"),
+		ModerationLog: os.Getenv("MODERATION_LOG"),
+		TenantFile:    os.Getenv("TENANT_FILE"),
+		JobWorkers:    env.int("JOB_WORKERS", 4),
+		JobQueueDepth: env.int("JOB_QUEUE_DEPTH", 100),
+		JobStore:      getEnv("JOB_STORE", "memory"),
+		JobTTL:        env.duration("JOB_TTL", 24*time.Hour),
+		RedisURL:      getEnv("REDIS_URL", "redis://redis:6379/0"),
+		LimitStore:    getEnv("RATE_LIMIT_STORE", "memory"),
+		DefaultPlan:   getEnv("DEFAULT_PLAN", "free"),
+
+		IdempotencyStore: getEnv("IDEMPOTENCY_STORE", "memory"),
+		IdempotencyTTL:   env.duration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		WebhookSecret:      os.Getenv("WEBHOOK_SECRET"),
+		WebhookMaxAttempts: env.int("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookTimeout:     env.duration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookAllowLocal:  env.bool("WEBHOOK_ALLOW_LOCAL", false),
+
+		DemoScenarioDir: os.Getenv("DEMO_SCENARIO_DIR"),
+		DemoCacheTTL:    env.duration("DEMO_CACHE_TTL", time.Hour),
+
+		PipelineConfig: os.Getenv("PIPELINE_CONFIG"),
+
+		ImageProvider:    os.Getenv("IMAGE_PROVIDER"),
+		ImageTimeout:     env.duration("IMAGE_TIMEOUT", 2*time.Minute),
+		GeminiAPIKey:     os.Getenv("GEMINI_API_KEY"),
+		GeminiBaseURL:    os.Getenv("GEMINI_BASE_URL"),
+		GeminiImageModel: os.Getenv("GEMINI_IMAGE_MODEL"),
+		A1111BaseURL:     os.Getenv("A1111_BASE_URL"),
+		OpenAIAPIKey:     os.Getenv("OPENAI_API_KEY"),
+		OpenAIBaseURL:    os.Getenv("OPENAI_BASE_URL"),
+		OpenAIImageModel: os.Getenv("OPENAI_IMAGE_MODEL"),
+
+		ImageMaxAttempts:    env.int("IMAGE_MAX_ATTEMPTS", 3),
+		ImageRetryBudget:    env.float("IMAGE_RETRY_BUDGET", 0.2),
+		BreakerFailures:     env.int("BREAKER_FAILURES", 5),
+		BreakerOpenDuration: env.duration("BREAKER_OPEN_DURATION", 30*time.Second),
+
+		BatchConcurrency: env.int("BATCH_CONCURRENCY", 4),
+		BatchMaxItems:    env.int("BATCH_MAX_ITEMS", 500),
+
+		FeedInterval:    env.duration("FEED_INTERVAL", 500*time.Millisecond),
+		FeedBuffer:      env.int("FEED_BUFFER", 256),
+		FeedSlowTimeout: env.duration("FEED_SLOW_TIMEOUT", 10*time.Second),
+	}
+	return config, env.err()
+}
+
+func (c Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// Validate rejects configurations that are unsafe to serve with
+func (c Config) Validate() error {
+	if c.Port == "" || c.GRPCPort == "" {
+		return fmt.Errorf("PORT and GRPC_PORT must not be empty")
+	}
+	if c.AuthDisabled && c.IsProduction() {
+		return fmt.Errorf("AUTH_DISABLED must not be set when ENV=production")
+	}
+	if c.AuthDisabled && (c.JWTSecret != "" || c.JWTPublicKey != "") {
+		return fmt.Errorf("AUTH_DISABLED cannot be combined with JWT_SECRET or JWT_PUBLIC_KEY_FILE")
+	}
+	if !c.AuthDisabled && c.JWTSecret == "" && c.JWTPublicKey == "" {
+		return fmt.Errorf("JWT_SECRET or JWT_PUBLIC_KEY_FILE is required; set AUTH_DISABLED=true to run without authentication in development")
+	}
+	if c.JobStore != "memory" && c.JobStore != "redis" {
+		return fmt.Errorf("JOB_STORE must be memory or redis, got %q", c.JobStore)
+	}
+	if c.IsProduction() && c.WebhookAllowLocal {
+		return fmt.Errorf("WEBHOOK_ALLOW_LOCAL must not be set when ENV=production")
+	}
+	if c.IsProduction() && c.WebhookSecret == "" {
+		return fmt.Errorf("WEBHOOK_SECRET is required when ENV=production")
+	}
+	if c.LimitStore != "memory" && c.LimitStore != "redis" {
+		return fmt.Errorf("RATE_LIMIT_STORE must be memory or redis, got %q", c.LimitStore)
+	}
+	if c.IdempotencyStore != "memory" && c.IdempotencyStore != "redis" {
+		return fmt.Errorf("IDEMPOTENCY_STORE must be memory or redis, got %q", c.IdempotencyStore)
+	}
+	if c.JobWorkers < 1 || c.JobQueueDepth < 1 {
+		return fmt.Errorf("JOB_WORKERS and JOB_QUEUE_DEPTH must be positive")
+	}
+	if c.DemoCacheTTL < 0 {
+		return fmt.Errorf("DEMO_CACHE_TTL must not be negative")
+	}
+	if c.ImageTimeout <= 0 || c.ImageMaxAttempts < 1 || c.BreakerFailures < 1 || c.BreakerOpenDuration <= 0 {
+		return fmt.Errorf("IMAGE_TIMEOUT, IMAGE_MAX_ATTEMPTS, BREAKER_FAILURES and BREAKER_OPEN_DURATION must be positive")
+	}
+	if c.ImageRetryBudget < 0 {
+		return fmt.Errorf("IMAGE_RETRY_BUDGET must not be negative")
+	}
+	if c.BatchConcurrency < 1 || c.BatchMaxItems < 1 {
+		return fmt.Errorf("BATCH_CONCURRENCY and BATCH_MAX_ITEMS must be positive")
+	}
+	if c.FeedInterval <= 0 || c.FeedBuffer < 1 {
+		return fmt.Errorf("FEED_INTERVAL and FEED_BUFFER must be positive")
+	}
+	return nil
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envReader parses typed variables, collecting one error per variable
+// that is set but invalid
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) int(key string, fallback int) int {
+	return parseEnv(r, key, "integer", strconv.Atoi, fallback)
+}
+
+func (r *envReader) duration(key string, fallback time.Duration) time.Duration {
+	return parseEnv(r, key, "duration", time.ParseDuration, fallback)
+}
+
+func (r *envReader) float(key string, fallback float64) float64 {
+	return parseEnv(r, key, "number", func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	}, fallback)
+}
+
+func (r *envReader) bool(key string, fallback bool) bool {
+	return parseEnv(r, key, "boolean", strconv.ParseBool, fallback)
+}
+
+func (r *envReader) err() error {
+	return errors.Join(r.errs...)
+}
+
+func parseEnv[T any](r *envReader, key string, kind string, parse func(string) (T, error), fallback T) T {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := parse(raw)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s=%q is not a valid %s", key, raw, kind))
+		return fallback
+	}
+	return value
+}
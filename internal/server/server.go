@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	grpcapi "geminizer-enterprise/api/grpc"
+	pb "geminizer-enterprise/api/proto/geminizer/v1"
+	v1 "geminizer-enterprise/api/v1"
+	"geminizer-enterprise/internal/auth"
+	"geminizer-enterprise/internal/core/ai"
+	"geminizer-enterprise/internal/core/ai/management"
+	"geminizer-enterprise/internal/core/domain"
+	"geminizer-enterprise/internal/core/services"
+	"geminizer-enterprise/internal/demo"
+	"geminizer-enterprise/internal/feed"
+	"geminizer-enterprise/internal/idempotency"
+	"geminizer-enterprise/internal/imaging"
+	"geminizer-enterprise/internal/jobs"
+	"geminizer-enterprise/internal/profiles"
+	"geminizer-enterprise/internal/quota"
+	"geminizer-enterprise/internal/repository/local"
+	"geminizer-enterprise/internal/repository/memory"
+	"geminizer-enterprise/internal/tenant"
+	"geminizer-enterprise/internal/webhooks"
+)
+
+// Server owns the service graph and the HTTP router built on it
+type Server struct {
+	config   Config
+	logger   services.Logger
+	graph    *services.ServiceGraph
+	jobs     *jobs.Manager
+	webhooks *webhooks.Dispatcher
+	feed     *feed.Hub
+	watcher  *feed.Watcher
+	router   *gin.Engine
+	grpc     *grpc.Server
+}
+
+// New builds the service graph once and registers every HTTP route and
+// gRPC service on it
+func New(config Config, logger services.Logger) (*Server, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	repo, err := newHistoryRepository(config)
+	if err != nil {
+		return nil, err
+	}
+
+	moderation := ai.NewModerationHistory()
+	if config.ModerationLog != "" {
+		if err := moderation.Persist(config.ModerationLog); err != nil {
+			return nil, err
+		}
+	}
+
+	tenants := tenant.NewRegistry()
+	if config.TenantFile != "" {
+		if err := tenants.Persist(config.TenantFile); err != nil {
+			return nil, err
+		}
+	}
+
+	pipelines, err := newPipelineConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := services.NewServiceGraph(repo, logger)
+	graph.SetTenants(tenants)
+	graph.SetModerationHistory(moderation)
+	if err := pipelines.Apply(graph); err != nil {
+		return nil, err
+	}
+	if err := graph.SetImageProviders(newImageProviders(config), config.ImageProvider, newRoutingConfig(config)); err != nil {
+		return nil, err
+	}
+
+	dispatcher := webhooks.NewDispatcher(webhooks.Config{
+		Secret:      config.WebhookSecret,
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: config.WebhookMaxAttempts,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    10 * time.Minute,
+		Timeout:     config.WebhookTimeout,
+		DeadLetters: 1000,
+
+		AllowPrivateTargets: config.WebhookAllowLocal,
+	}, logger)
+	graph.SetNotifier(dispatcher)
+
+	catalog, err := newDemoCatalog(config)
+	if err != nil {
+		return nil, err
+	}
+	demos := demo.NewRunner(catalog, graph, config.DemoCacheTTL)
+
+	aiManager := management.NewManagerAgent()
+	for _, provider := range graph.ImageProviders() {
+		aiManager.RegisterProvider(provider.Name)
+	}
+	graph.SetProviderObserver(aiManager)
+	healthMonitor := management.NewHealthMonitor()
+	healthMonitor.SetThresholds(pipelines.HealthThresholds())
+	handler := v1.NewImageHandler(graph, aiManager, healthMonitor, moderation, demos)
+
+	hub := feed.NewHub(feed.Config{
+		Buffer:      config.FeedBuffer,
+		SlowTimeout: config.FeedSlowTimeout,
+	})
+	watcher := feed.NewWatcher(hub, aiManager, healthMonitor, moderation, config.FeedInterval)
+
+	jobStore, err := newJobStore(config)
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := newLimiter(config)
+	if err != nil {
+		return nil, err
+	}
+	jobManager := jobs.NewManager(jobStore, graph, jobs.Config{
+		Workers:    config.JobWorkers,
+		QueueDepth: config.JobQueueDepth,
+		Quota:      limiter,
+	})
+
+	authenticator, err := newAuthenticator(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyStore, err := newIdempotencyStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery())
+	groups := v1.NewGroups(router.Group("/api/v1"), v1.Middleware{
+		Authenticate: v1.Authenticate(authenticator),
+		Tenancy:      v1.Tenancy(tenants),
+		RateLimit:    v1.RateLimit(limiter),
+		Idempotency:  v1.Idempotency(idempotencyStore, config.IdempotencyTTL),
+		Quota:        v1.Quota(limiter),
+	})
+	v1.RegisterRoutes(groups, handler)
+	v1.RegisterJobRoutes(groups, v1.NewJobHandler(jobManager))
+	v1.RegisterBatchRoutes(groups, v1.NewBatchHandler(graph, limiter, v1.BatchConfig{
+		Concurrency: config.BatchConcurrency,
+		MaxItems:    config.BatchMaxItems,
+	}))
+	v1.RegisterWebhookRoutes(groups, v1.NewWebhookHandler(dispatcher))
+	v1.RegisterFeedRoutes(groups, v1.NewFeedHandler(hub, watcher))
+	v1.RegisterTenantRoutes(groups, v1.NewTenantHandler(tenants))
+
+	grpcServer := grpc.NewServer(grpcapi.NewPolicy(authenticator, tenants, limiter).ServerOptions()...)
+	pb.RegisterGeminizerServiceServer(grpcServer, grpcapi.NewServer(graph, aiManager))
+
+	return &Server{
+		config:   config,
+		logger:   logger,
+		graph:    graph,
+		jobs:     jobManager,
+		webhooks: dispatcher,
+		feed:     hub,
+		watcher:  watcher,
+		router:   router,
+		grpc:     grpcServer,
+	}, nil
+}
+
+// newAuthenticator verifies bearer tokens. Only with AUTH_DISABLED set
+// (development only, Validate enforces this) does every request run as a
+// local admin so the API stays usable on a laptop.
+func newAuthenticator(config Config, logger services.Logger) (auth.Authenticator, error) {
+	if config.AuthDisabled {
+		logger.Info("authentication disabled: every request runs as a local admin", "env", config.Env)
+		return auth.Static{Identity: domain.Identity{
+			UserID: "local-dev",
+			Roles:  []string{domain.RoleAdmin},
+		}}, nil
+	}
+
+	authConfig := auth.Config{
+		Secret: []byte(config.JWTSecret),
+		Issuer: config.JWTIssuer,
+		Leeway: 30 * time.Second,
+	}
+	if config.JWTPublicKey != "" {
+		key, err := auth.LoadRSAPublicKey(config.JWTPublicKey)
+		if err != nil {
+			return nil, err
+		}
+		authConfig.PublicKey = key
+	}
+
+	verifier, err := auth.NewVerifier(authConfig)
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+func newLimiter(config Config) (*quota.Limiter, error) {
+	var store quota.Store = quota.NewMemoryStore()
+	if config.LimitStore == "redis" {
+		redisStore, err := quota.NewRedisStore(config.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("connect rate limit store: %v", err)
+		}
+		store = redisStore
+	}
+	return quota.NewLimiter(store, quota.DefaultPlans, config.DefaultPlan)
+}
+
+func newIdempotencyStore(config Config) (idempotency.Store, error) {
+	if config.IdempotencyStore == "redis" {
+		store, err := idempotency.NewRedisStore(config.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("connect idempotency store: %v", err)
+		}
+		return store, nil
+	}
+	return idempotency.NewMemoryStore(), nil
+}
+
+func newJobStore(config Config) (jobs.Store, error) {
+	if config.JobStore == "redis" {
+		store, err := jobs.NewRedisStore(config.RedisURL, config.JobTTL)
+		if err != nil {
+			return nil, fmt.Errorf("connect job store: %v", err)
+		}
+		return store, nil
+	}
+	return jobs.NewMemoryStore(config.JobTTL), nil
+}
+
+// newHistoryRepository uses the local store when HISTORY_PATH is set. The
+// production container has a read-only filesystem, so the default is memory.
+func newHistoryRepository(config Config) (services.HistoryRepository, error) {
+	if config.HistoryPath == "" {
+		return memory.NewHistoryRepository(), nil
+	}
+
+	store, err := local.OpenHistoryStore(config.HistoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("open history store: %v", err)
+	}
+	return store, nil
+}
+
+func newDemoCatalog(config Config) (*demo.Catalog, error) {
+	if config.DemoScenarioDir != "" {
+		return demo.LoadCatalogDir(config.DemoScenarioDir)
+	}
+	return demo.BuiltinCatalog()
+}
+
+func newPipelineConfig(config Config) (*profiles.Config, error) {
+	if config.PipelineConfig != "" {
+		return profiles.Load(config.PipelineConfig)
+	}
+	return profiles.Builtin()
+}
+
+func newImageProviders(config Config) []services.ImageProvider {
+	return imaging.Providers(imaging.Config{
+		Timeout: config.ImageTimeout,
+		Gemini: imaging.GeminiConfig{
+			BaseURL: config.GeminiBaseURL,
+			APIKey:  config.GeminiAPIKey,
+			Model:   config.GeminiImageModel,
+		},
+		Automatic1111: imaging.Automatic1111Config{
+			BaseURL: config.A1111BaseURL,
+		},
+		OpenAI: imaging.OpenAIConfig{
+			BaseURL: config.OpenAIBaseURL,
+			APIKey:  config.OpenAIAPIKey,
+			Model:   config.OpenAIImageModel,
+		},
+	})
+}
+
+func newRoutingConfig(config Config) services.RoutingConfig {
+	return services.RoutingConfig{
+		Timeout:          config.ImageTimeout,
+		MaxAttempts:      config.ImageMaxAttempts,
+		FailureThreshold: config.BreakerFailures,
+		OpenDuration:     config.BreakerOpenDuration,
+		RetryBudget:      config.ImageRetryBudget,
+	}
+}
+
+// Graph exposes the shared service graph to other transports
+func (s *Server) Graph() *services.ServiceGraph {
+	return s.graph
+}
+
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// Run serves until ctx is cancelled, then drains in-flight requests
+func (s *Server) Run(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              ":" + s.config.Port,
+		Handler:           s.router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Workers are stopped after the listener drains, not when ctx is cancelled
+	workerCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+	s.jobs.Start(workerCtx)
+
+	// Webhooks stop last so the outcome of every job still gets queued
+	webhookCtx, stopWebhooks := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWebhooks()
+	s.webhooks.Start(webhookCtx)
+
+	go s.watcher.Run(ctx)
+
+	grpcListener, err := net.Listen("tcp", ":"+s.config.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("listen for gRPC: %v", err)
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		s.logger.Info("server listening", "port", s.config.Port, "env", s.config.Env)
+		errs <- httpServer.ListenAndServe()
+	}()
+	go func() {
+		s.logger.Info("gRPC server listening", "port", s.config.GRPCPort)
+		errs <- s.grpc.Serve(grpcListener)
+	}()
+
+	select {
+	case err := <-errs:
+		s.feed.Close()
+		s.grpc.Stop()
+		httpServer.Close()
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("server shutting down")
+
+	// Shutdown does not wait for hijacked WebSocket connections; close
+	// them so dashboards see a going-away frame instead of a dropped socket
+	s.feed.Close()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	go func() {
+		// GracefulStop waits for open streams; cut them off at the deadline
+		<-shutdownCtx.Done()
+		s.grpc.Stop()
+	}()
+	err = httpServer.Shutdown(shutdownCtx)
+	s.grpc.GracefulStop()
+	stopWorkers()
+	s.jobs.Wait()
+	stopWebhooks()
+	s.webhooks.Wait()
+	return err
+}
@@ -0,0 +1,289 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/domain"
+)
+
+var (
+	ErrNotFound  = errors.New("tenant not found")
+	ErrExists    = errors.New("tenant already exists")
+	ErrNotMember = errors.New("user is not assigned to this tenant")
+)
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ValidID reports whether id can name a tenant
+func ValidID(id string) bool {
+	return validID.MatchString(id)
+}
+
+// Registry holds the tenants and which tenant each user belongs to. It is
+// in-memory by default; Persist keeps it in a JSON file so tenants and
+// assignments survive restarts.
+type Registry struct {
+	mu      sync.RWMutex
+	tenants map[string]*domain.Tenant
+	members map[string]string // user ID to tenant ID
+	path    string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		tenants: make(map[string]*domain.Tenant),
+		members: make(map[string]string),
+	}
+}
+
+// OpenRegistry loads the registry at path, creating it on the first change
+func OpenRegistry(path string) (*Registry, error) {
+	registry := NewRegistry()
+	if err := registry.Persist(path); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// snapshot is the file format
+type snapshot struct {
+	Tenants []*domain.Tenant  `json:"tenants"`
+	Members map[string]string `json:"members"`
+}
+
+// Persist loads path, if it exists, and saves every later change there
+func (r *Registry) Persist(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create tenant directory: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("open tenants: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(data) > 0 {
+		var loaded snapshot
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return fmt.Errorf("read tenants: %v", err)
+		}
+		for _, tenant := range loaded.Tenants {
+			r.tenants[tenant.ID] = tenant
+		}
+		for userID, tenantID := range loaded.Members {
+			r.members[userID] = tenantID
+		}
+	}
+
+	r.path = path
+	return nil
+}
+
+// Create registers a new tenant
+func (r *Registry) Create(tenant domain.Tenant) (*domain.Tenant, error) {
+	if !ValidID(tenant.ID) {
+		return nil, fmt.Errorf("invalid tenant ID %q: use lowercase letters, digits, - and _", tenant.ID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tenants[tenant.ID]; exists {
+		return nil, ErrExists
+	}
+
+	now := time.Now().UTC()
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+	r.tenants[tenant.ID] = &tenant
+
+	if err := r.save(); err != nil {
+		delete(r.tenants, tenant.ID)
+		return nil, err
+	}
+	return copyTenant(&tenant), nil
+}
+
+// Update replaces a tenant's name, plan, styles, policy and pipelines
+func (r *Registry) Update(tenant domain.Tenant) (*domain.Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, exists := r.tenants[tenant.ID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	tenant.CreatedAt = previous.CreatedAt
+	tenant.UpdatedAt = time.Now().UTC()
+	r.tenants[tenant.ID] = &tenant
+
+	if err := r.save(); err != nil {
+		r.tenants[tenant.ID] = previous
+		return nil, err
+	}
+	return copyTenant(&tenant), nil
+}
+
+// Get returns a copy, so callers may read it without holding the lock
+func (r *Registry) Get(id string) (*domain.Tenant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenant, ok := r.tenants[id]
+	if !ok {
+		return nil, false
+	}
+	return copyTenant(tenant), true
+}
+
+// List returns every tenant ordered by ID
+func (r *Registry) List() []*domain.Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]*domain.Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, copyTenant(tenant))
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants
+}
+
+// Assign moves a user into a tenant, out of any tenant they were in
+func (r *Registry) Assign(userID string, tenantID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tenants[tenantID]; !exists {
+		return ErrNotFound
+	}
+
+	previous, had := r.members[userID]
+	r.members[userID] = tenantID
+
+	if err := r.save(); err != nil {
+		if had {
+			r.members[userID] = previous
+		} else {
+			delete(r.members, userID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Unassign removes a user from tenantID; it fails if they are not in it
+func (r *Registry) Unassign(userID string, tenantID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.members[userID] != tenantID {
+		return ErrNotMember
+	}
+
+	delete(r.members, userID)
+	if err := r.save(); err != nil {
+		r.members[userID] = tenantID
+		return err
+	}
+	return nil
+}
+
+// Members lists a tenant's assigned users in order
+func (r *Registry) Members(tenantID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]string, 0)
+	for userID, assigned := range r.members {
+		if assigned == tenantID {
+			members = append(members, userID)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// Resolve puts the caller in their workspace. An assignment made through
+// the admin API wins over the tenant claim in the token, and a tenant's
+// plan replaces the token's so the whole workspace shares one plan.
+func (r *Registry) Resolve(identity domain.Identity) domain.Identity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if tenantID, ok := r.members[identity.UserID]; ok {
+		identity.TenantID = tenantID
+	}
+	if tenant, ok := r.tenants[identity.TenantID]; ok && tenant.Plan != "" {
+		identity.Plan = tenant.Plan
+	}
+	return identity
+}
+
+// save rewrites the file atomically; callers hold the write lock
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	state := snapshot{
+		Tenants: make([]*domain.Tenant, 0, len(r.tenants)),
+		Members: r.members,
+	}
+	for _, tenant := range r.tenants {
+		state.Tenants = append(state.Tenants, tenant)
+	}
+	sort.Slice(state.Tenants, func(i, j int) bool { return state.Tenants[i].ID < state.Tenants[j].ID })
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".tenants-*")
+	if err != nil {
+		return fmt.Errorf("save tenants: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save tenants: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save tenants: %v", err)
+	}
+
+	return os.Rename(tmp.Name(), r.path)
+}
+
+func copyTenant(tenant *domain.Tenant) *domain.Tenant {
+	copied := *tenant
+	if tenant.Styles != nil {
+		copied.Styles = make(map[string]domain.CustomStyle, len(tenant.Styles))
+		for name, style := range tenant.Styles {
+			copied.Styles[name] = style
+		}
+	}
+	if tenant.Pipelines != nil {
+		copied.Pipelines = make(map[string][]string, len(tenant.Pipelines))
+		for tier, stages := range tenant.Pipelines {
+			copied.Pipelines[tier] = append([]string(nil), stages...)
+		}
+	}
+	copied.Policy.BlockedTerms = append([]string(nil), tenant.Policy.BlockedTerms...)
+	copied.Policy.BlockedStyles = append([]string(nil), tenant.Policy.BlockedStyles...)
+	return &copied
+}
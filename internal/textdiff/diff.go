@@ -0,0 +1,106 @@
+// Package textdiff computes word-level differences between two prompts.
+package textdiff
+
+import "strings"
+
+type OpKind int
+
+const (
+	Equal OpKind = iota
+	Insert
+	Delete
+)
+
+// Op is a run of words that are kept, added or removed
+type Op struct {
+	Kind OpKind
+	Text string
+}
+
+// Words diffs before and after on whitespace-separated words using the
+// longest common subsequence. Prompts are a few hundred words at most,
+// so the quadratic table is fine.
+func Words(before string, after string) []Op {
+	a := strings.Fields(before)
+	b := strings.Fields(after)
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = appendOp(ops, Equal, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = appendOp(ops, Delete, a[i])
+			i++
+		default:
+			ops = appendOp(ops, Insert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = appendOp(ops, Delete, a[i])
+	}
+	for ; j < len(b); j++ {
+		ops = appendOp(ops, Insert, b[j])
+	}
+
+	return ops
+}
+
+// appendOp merges consecutive words of the same kind into one op
+func appendOp(ops []Op, kind OpKind, word string) []Op {
+	if n := len(ops); n > 0 && ops[n-1].Kind == kind {
+		ops[n-1].Text += " " + word
+		return ops
+	}
+	return append(ops, Op{Kind: kind, Text: word})
+}
+
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiReset = "\033[0m"
+)
+
+// Render formats ops for a terminal. With color, removed words are red and
+// added words green; without it they are wrapped in [-...-] and {+...+}.
+func Render(ops []Op, color bool) string {
+	parts := make([]string, 0, len(ops))
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			parts = append(parts, op.Text)
+		case Delete:
+			if color {
+				parts = append(parts, ansiRed+op.Text+ansiReset)
+			} else {
+				parts = append(parts, "[-"+op.Text+"-]")
+			}
+		case Insert:
+			if color {
+				parts = append(parts, ansiGreen+op.Text+ansiReset)
+			} else {
+				parts = append(parts, "{+"+op.Text+"+}")
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,287 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type Config struct {
+	Secret      string // default signing secret
+	Workers     int
+	QueueSize   int
+	MaxAttempts int
+	BaseDelay   time.Duration // first retry delay, doubled per attempt
+	MaxDelay    time.Duration
+	Timeout     time.Duration // per attempt
+	DeadLetters int           // how many failed deliveries to keep
+	// AllowPrivateTargets lets deliveries reach loopback and private
+	// addresses; for development against a local receiver only
+	AllowPrivateTargets bool
+}
+
+type delivery struct {
+	endpoint Endpoint
+	event    Event
+}
+
+// Dispatcher implements services.Notifier by queueing events and
+// delivering them from a small worker pool
+type Dispatcher struct {
+	config      Config
+	client      *http.Client
+	endpoints   *EndpointRegistry
+	deadLetters *DeadLetterList
+	logger      services.Logger
+	queue       chan delivery
+	wg          sync.WaitGroup
+}
+
+func NewDispatcher(config Config, logger services.Logger) *Dispatcher {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	return &Dispatcher{
+		config:      config,
+		client:      newClient(config.Timeout, config.AllowPrivateTargets),
+		endpoints:   NewEndpointRegistry(),
+		deadLetters: NewDeadLetterList(config.DeadLetters),
+		logger:      logger,
+		queue:       make(chan delivery, config.QueueSize),
+	}
+}
+
+func (d *Dispatcher) Endpoints() *EndpointRegistry {
+	return d.endpoints
+}
+
+func (d *Dispatcher) DeadLetters() *DeadLetterList {
+	return d.deadLetters
+}
+
+// Start launches the delivery workers; they stop when ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.work(ctx)
+	}
+}
+
+// Wait blocks until every worker has stopped
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Notify sends the event to the request's webhook URL, or to the tenant's
+// endpoint when the request named none. Events with neither are dropped.
+func (d *Dispatcher) Notify(ctx context.Context, outcome services.OutcomeEvent) {
+	endpoint, ok := d.resolve(outcome)
+	if !ok {
+		return
+	}
+
+	event := Event{
+		ID:        newEventID(),
+		Type:      outcome.Type,
+		CreatedAt: time.Now().UTC(),
+		UserID:    outcome.UserID,
+		TenantID:  outcome.TenantID,
+		JobID:     outcome.JobID,
+		Data:      outcome.Data,
+	}
+
+	if err := ValidateURL(endpoint.URL); err != nil {
+		d.deadLetter(delivery{endpoint, event}, 0, err)
+		return
+	}
+
+	d.enqueue(delivery{endpoint, event})
+}
+
+// Retry moves a dead letter back onto the queue with a fresh set of attempts
+func (d *Dispatcher) Retry(eventID string) error {
+	letter, ok := d.deadLetters.Take(eventID)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+
+	d.enqueue(delivery{letter.endpoint, letter.Event})
+	return nil
+}
+
+func (d *Dispatcher) resolve(outcome services.OutcomeEvent) (Endpoint, bool) {
+	if outcome.WebhookURL != "" {
+		return Endpoint{URL: outcome.WebhookURL}, true
+	}
+	if outcome.TenantID != "" {
+		return d.endpoints.Get(outcome.TenantID)
+	}
+	return Endpoint{}, false
+}
+
+func (d *Dispatcher) enqueue(job delivery) {
+	select {
+	case d.queue <- job:
+	default:
+		d.deadLetter(job, 0, errors.New("delivery queue full"))
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.drain()
+			return
+		case job := <-d.queue:
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+// drain dead-letters whatever is still queued at shutdown so it can be
+// retried from the admin API instead of being lost
+func (d *Dispatcher) drain() {
+	for {
+		select {
+		case job := <-d.queue:
+			d.deadLetter(job, 0, errors.New("not delivered before shutdown"))
+		default:
+			return
+		}
+	}
+}
+
+// deliver retries with exponential backoff until the endpoint accepts the
+// event, rejects it permanently, or the attempts run out
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	body, err := json.Marshal(job.event)
+	if err != nil {
+		d.deadLetter(job, 0, err)
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		retryable, err := d.post(ctx, job, body)
+		if err == nil {
+			return
+		}
+		lastErr = err
+
+		if !retryable || attempt == d.config.MaxAttempts {
+			d.deadLetter(job, attempt, lastErr)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			d.deadLetter(job, attempt, fmt.Errorf("shutdown before retry: %v", lastErr))
+			return
+		case <-time.After(d.backoff(attempt)):
+		}
+	}
+}
+
+// post makes one attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(ctx context.Context, job delivery, body []byte) (bool, error) {
+	secret := job.endpoint.Secret
+	if secret == "" {
+		secret = d.config.Secret
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, job.endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Geminizer-Webhooks/1.0")
+	request.Header.Set(EventHeader, job.event.Type)
+	request.Header.Set(DeliveryHeader, job.event.ID)
+	request.Header.Set(SignatureHeader, Sign(secret, time.Now().Unix(), body))
+
+	response, err := d.client.Do(request)
+	if errors.Is(err, ErrBlockedAddress) {
+		return false, err
+	}
+	if err != nil {
+		return true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	response.Body.Close()
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusRequestTimeout, response.StatusCode == http.StatusTooManyRequests, response.StatusCode >= 500:
+		return true, fmt.Errorf("endpoint returned %s", response.Status)
+	}
+	return false, fmt.Errorf("endpoint rejected delivery: %s", response.Status)
+}
+
+// backoff doubles BaseDelay per attempt up to MaxDelay and adds up to 20%
+// jitter so failed endpoints are not hit by synchronised retries
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.BaseDelay << (attempt - 1)
+	if delay <= 0 || (d.config.MaxDelay > 0 && delay > d.config.MaxDelay) {
+		delay = d.config.MaxDelay
+	}
+
+	if jitterRange := int64(delay / 5); jitterRange > 0 {
+		if jitter, err := rand.Int(rand.Reader, big.NewInt(jitterRange)); err == nil {
+			delay += time.Duration(jitter.Int64())
+		}
+	}
+	return delay
+}
+
+func (d *Dispatcher) deadLetter(job delivery, attempts int, err error) {
+	d.logger.Error("webhook delivery failed", err, "event_id", job.event.ID, "type", job.event.Type, "url", job.endpoint.URL, "attempts", attempts)
+	d.deadLetters.Add(&DeadLetter{
+		Event:     job.event,
+		URL:       job.endpoint.URL,
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  time.Now().UTC(),
+		endpoint:  job.endpoint,
+	})
+}
+
+// ValidateURL accepts absolute http and https URLs only. Where the host
+// points is checked when a delivery connects, see newClient.
+func ValidateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %v", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: must be absolute http or https", raw)
+	}
+	return nil
+}
+
+func newEventID() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return "evt_" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"geminizer-enterprise/internal/core/services"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{})         {}
+func (nopLogger) Error(string, error, ...interface{}) {}
+
+// received is one delivery as the endpoint saw it
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// testEndpoint answers deliveries with the statuses in order, repeating
+// the last one, and records what it was sent
+type testEndpoint struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	statuses   []int
+	deliveries []received
+}
+
+func newTestEndpoint(t *testing.T, statuses ...int) *testEndpoint {
+	endpoint := &testEndpoint{statuses: statuses}
+	endpoint.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		endpoint.mu.Lock()
+		endpoint.deliveries = append(endpoint.deliveries, received{header: r.Header.Clone(), body: body})
+		status := endpoint.statuses[0]
+		if len(endpoint.statuses) > 1 {
+			endpoint.statuses = endpoint.statuses[1:]
+		}
+		endpoint.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(endpoint.Close)
+	return endpoint
+}
+
+func (e *testEndpoint) received() []received {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]received(nil), e.deliveries...)
+}
+
+func startDispatcher(t *testing.T, config Config) *Dispatcher {
+	if config.QueueSize == 0 {
+		config.QueueSize = 10
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Second
+	}
+	// httptest endpoints listen on loopback
+	config.AllowPrivateTargets = true
+	dispatcher := NewDispatcher(config, nopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		dispatcher.Wait()
+	})
+	return dispatcher
+}
+
+// waitFor polls until done reports true or a few seconds pass
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDeliversSignedGenerationEvent(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusOK)
+	dispatcher := startDispatcher(t, Config{Secret: "default-secret", MaxAttempts: 3})
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{
+		Type:       services.EventGenerationCompleted,
+		WebhookURL: endpoint.URL,
+		UserID:     "user-1",
+		TenantID:   "acme",
+		JobID:      "job-1",
+		Data:       map[string]string{"image_url": "https://images.example/1.png"},
+	})
+	waitFor(t, "the delivery", func() bool { return len(endpoint.received()) == 1 })
+
+	delivery := endpoint.received()[0]
+	if err := VerifySignature("default-secret", delivery.header.Get(SignatureHeader), delivery.body, time.Minute); err != nil {
+		t.Errorf("signature: %v", err)
+	}
+	if got := delivery.header.Get(EventHeader); got != services.EventGenerationCompleted {
+		t.Errorf("%s = %q", EventHeader, got)
+	}
+
+	var event Event
+	if err := json.Unmarshal(delivery.body, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if delivery.header.Get(DeliveryHeader) != event.ID || event.ID == "" {
+		t.Errorf("%s = %q, event id %q", DeliveryHeader, delivery.header.Get(DeliveryHeader), event.ID)
+	}
+	if event.Type != services.EventGenerationCompleted || event.UserID != "user-1" || event.TenantID != "acme" || event.JobID != "job-1" {
+		t.Errorf("event = %+v", event)
+	}
+	if data, _ := event.Data.(map[string]interface{}); data["image_url"] != "https://images.example/1.png" {
+		t.Errorf("data = %v", event.Data)
+	}
+}
+
+func TestTenantEndpointSignsWithItsOwnSecret(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusNoContent)
+	dispatcher := startDispatcher(t, Config{Secret: "default-secret", MaxAttempts: 1})
+	dispatcher.Endpoints().Set("acme", Endpoint{URL: endpoint.URL, Secret: "acme-secret"})
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationFailed, TenantID: "acme"})
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationFailed, TenantID: "unregistered"})
+	waitFor(t, "the delivery", func() bool { return len(endpoint.received()) == 1 })
+
+	delivery := endpoint.received()[0]
+	if err := VerifySignature("acme-secret", delivery.header.Get(SignatureHeader), delivery.body, time.Minute); err != nil {
+		t.Errorf("signature with the tenant secret: %v", err)
+	}
+	if len(dispatcher.DeadLetters().List()) != 0 {
+		t.Errorf("an event with no endpoint was dead-lettered")
+	}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	dispatcher := startDispatcher(t, Config{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationCompleted, WebhookURL: endpoint.URL})
+	waitFor(t, "the third attempt", func() bool { return len(endpoint.received()) == 3 })
+
+	// Every attempt carries the same event
+	deliveries := endpoint.received()
+	for _, delivery := range deliveries[1:] {
+		if string(delivery.body) != string(deliveries[0].body) {
+			t.Errorf("retry sent %s, first attempt sent %s", delivery.body, deliveries[0].body)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := len(endpoint.received()); n != 3 {
+		t.Errorf("%d attempts after a success, want 3", n)
+	}
+	if len(dispatcher.DeadLetters().List()) != 0 {
+		t.Errorf("a delivered event was dead-lettered")
+	}
+}
+
+func TestRejectedDeliveryIsNotRetried(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusBadRequest)
+	dispatcher := startDispatcher(t, Config{MaxAttempts: 5, BaseDelay: time.Millisecond})
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationCompleted, WebhookURL: endpoint.URL})
+	waitFor(t, "the dead letter", func() bool { return len(dispatcher.DeadLetters().List()) == 1 })
+
+	if letter := dispatcher.DeadLetters().List()[0]; letter.Attempts != 1 {
+		t.Errorf("dead letter after %d attempts, want 1", letter.Attempts)
+	}
+	if n := len(endpoint.received()); n != 1 {
+		t.Errorf("%d attempts, want 1", n)
+	}
+}
+
+func TestExhaustedDeliveryCanBeRetried(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	dispatcher := startDispatcher(t, Config{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationCompleted, WebhookURL: endpoint.URL})
+	waitFor(t, "the dead letter", func() bool { return len(dispatcher.DeadLetters().List()) == 1 })
+
+	letter := dispatcher.DeadLetters().List()[0]
+	if letter.Attempts != 2 || letter.URL != endpoint.URL {
+		t.Errorf("dead letter = %+v, want 2 attempts to the endpoint", letter)
+	}
+
+	if err := dispatcher.Retry(letter.Event.ID); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	waitFor(t, "the retried delivery", func() bool { return len(endpoint.received()) == 3 })
+	if err := dispatcher.Retry(letter.Event.ID); err != ErrDeadLetterNotFound {
+		t.Errorf("second Retry: err = %v, want ErrDeadLetterNotFound", err)
+	}
+}
+
+func TestRefusesPrivateTargets(t *testing.T) {
+	endpoint := newTestEndpoint(t, http.StatusOK)
+	dispatcher := NewDispatcher(Config{QueueSize: 10, MaxAttempts: 5, BaseDelay: time.Millisecond, Timeout: 5 * time.Second}, nopLogger{})
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher.Start(ctx)
+	defer func() {
+		cancel()
+		dispatcher.Wait()
+	}()
+
+	dispatcher.Notify(context.Background(), services.OutcomeEvent{Type: services.EventGenerationCompleted, WebhookURL: endpoint.URL})
+	waitFor(t, "the dead letter", func() bool { return len(dispatcher.DeadLetters().List()) == 1 })
+
+	letter := dispatcher.DeadLetters().List()[0]
+	if letter.Attempts != 1 || !strings.Contains(letter.LastError, ErrBlockedAddress.Error()) {
+		t.Errorf("dead letter = %+v, want one blocked attempt", letter)
+	}
+	if n := len(endpoint.received()); n != 0 {
+		t.Errorf("a loopback endpoint received %d deliveries", n)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"0.0.0.0":          false,
+		"::":               false,
+		"100.64.0.1":       false,
+		"::ffff:127.0.0.1": false,
+		"fd00::1":          false,
+		"224.0.0.1":        false,
+	}
+	for address, want := range cases {
+		if got := publicAddress(netip.MustParseAddr(address)); got != want {
+			t.Errorf("publicAddress(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := NewDispatcher(Config{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, nopLogger{})
+
+	cases := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+	for _, c := range cases {
+		// Jitter adds up to a fifth of the delay
+		if delay := dispatcher.backoff(c.attempt); delay < c.base || delay >= c.base+c.base/5 {
+			t.Errorf("backoff(%d) = %v, want [%v, %v)", c.attempt, delay, c.base, c.base+c.base/5)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package webhooks delivers signed outcome events to tenant and
+// per-request endpoints, retrying failures and keeping the ones that never
+// succeed in a dead-letter list.
+package webhooks
+
+import "time"
+
+// Event is the JSON body POSTed to an endpoint
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	UserID    string      `json:"user_id,omitempty"`
+	TenantID  string      `json:"tenant_id,omitempty"`
+	JobID     string      `json:"job_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+// Endpoint is where events go. An empty Secret means the dispatcher's
+// default secret signs them.
+type Endpoint struct {
+	URL    string `json:"url" validate:"required"`
+	Secret string `json:"secret,omitempty"`
+}
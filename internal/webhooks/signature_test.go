@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSignFormat(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := fmt.Sprintf("t=1700000000,v1=%s", hex.EncodeToString(mac.Sum(nil)))
+
+	if got := Sign("secret", 1700000000, body); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now().Unix()
+	header := Sign("secret", now, body)
+
+	if err := VerifySignature("secret", header, body, time.Minute); err != nil {
+		t.Fatalf("VerifySignature of a fresh signature: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		secret string
+		header string
+		body   string
+	}{
+		{"wrong secret", "other", header, string(body)},
+		{"altered body", "secret", header, `{"id":"evt_2"}`},
+		{"stale", "secret", Sign("secret", now-3600, body), string(body)},
+		{"no timestamp", "secret", "v1=" + signature("secret", now, body), string(body)},
+		{"no signature", "secret", fmt.Sprintf("t=%d", now), string(body)},
+		{"bad timestamp", "secret", "t=soon,v1=00", string(body)},
+		{"empty", "secret", "", string(body)},
+	}
+	for _, c := range cases {
+		if err := VerifySignature(c.secret, c.header, []byte(c.body), time.Minute); err != ErrInvalidSignature {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", c.name, err)
+		}
+	}
+}
+
+func TestVerifySignatureWithoutTolerance(t *testing.T) {
+	body := []byte("{}")
+	if err := VerifySignature("secret", Sign("secret", 1, body), body, 0); err != nil {
+		t.Errorf("zero tolerance rejected an old signature: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+)
+
+// EndpointRegistry maps tenants to their webhook endpoint
+type EndpointRegistry struct {
+	mu        sync.RWMutex
+	endpoints map[string]Endpoint
+}
+
+func NewEndpointRegistry() *EndpointRegistry {
+	return &EndpointRegistry{endpoints: make(map[string]Endpoint)}
+}
+
+func (r *EndpointRegistry) Set(tenantID string, endpoint Endpoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpoints[tenantID] = endpoint
+}
+
+func (r *EndpointRegistry) Get(tenantID string) (Endpoint, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoint, ok := r.endpoints[tenantID]
+	return endpoint, ok
+}
+
+func (r *EndpointRegistry) Delete(tenantID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.endpoints, tenantID)
+}
+
+// DeadLetter is an event that exhausted its retries
+type DeadLetter struct {
+	Event     Event     `json:"event"`
+	URL       string    `json:"url"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+
+	endpoint Endpoint
+}
+
+// DeadLetterList keeps the most recent failed deliveries, oldest dropped
+// first once it is full
+type DeadLetterList struct {
+	mu      sync.Mutex
+	letters []*DeadLetter
+	limit   int
+}
+
+func NewDeadLetterList(limit int) *DeadLetterList {
+	return &DeadLetterList{limit: limit}
+}
+
+func (l *DeadLetterList) Add(letter *DeadLetter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.letters = append(l.letters, letter)
+	if l.limit > 0 && len(l.letters) > l.limit {
+		l.letters = l.letters[len(l.letters)-l.limit:]
+	}
+}
+
+// List returns the dead letters, newest first
+func (l *DeadLetterList) List() []*DeadLetter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]*DeadLetter, 0, len(l.letters))
+	for i := len(l.letters) - 1; i >= 0; i-- {
+		result = append(result, l.letters[i])
+	}
+	return result
+}
+
+// Take removes and returns the dead letter for an event
+func (l *DeadLetterList) Take(eventID string) (*DeadLetter, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, letter := range l.letters {
+		if letter.Event.ID == eventID {
+			l.letters = append(l.letters[:i], l.letters[i+1:]...)
+			return letter, true
+		}
+	}
+	return nil, false
+}